@@ -0,0 +1,183 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+
+	"github.com/decred/dcrseeder/api"
+)
+
+// requirePost wraps an admin handler to only allow POST requests.
+func requirePost(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", appName)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminHost parses the host query parameter of an admin request, using
+// defaultPort when the host does not specify one.
+func adminHost(r *http.Request, defaultPort string) (netip.AddrPort, error) {
+	host := r.URL.Query().Get(api.Host)
+	addrPort, err := netip.ParseAddrPort(normalizeAddress(host, defaultPort))
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("invalid host %q", host)
+	}
+	return netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port()), nil
+}
+
+// adminRange parses the range query parameter of an admin request.
+func adminRange(r *http.Request) (netip.Prefix, error) {
+	s := r.URL.Query().Get(api.Range)
+	prefix, err := parsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid range %q", s)
+	}
+	return prefix, nil
+}
+
+// registerAdminHandlers adds the admin API to mux. All admin requests must be
+// authorized by auth.
+func registerAdminHandlers(mux *http.ServeMux, cfg *netConfig, auth *authorizer,
+	amgr *Manager, log *slog.Logger) {
+
+	defaultPort := cfg.netParams.DefaultPort
+	handle := func(path string, handler http.HandlerFunc) {
+		mux.HandleFunc(path, auth.require(requirePost(handler)))
+	}
+
+	handle(api.AdminBanPath, func(w http.ResponseWriter, r *http.Request) {
+		addrPort, err := adminHost(r, defaultPort)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		removed, err := amgr.Ban(addrPort.Addr())
+		if err != nil {
+			log.Error("Failed to save bans", "err", err)
+			http.Error(w, "failed to save bans", http.StatusInternalServerError)
+			return
+		}
+		log.Info("Banned", "addr", addrPort.Addr(), "removed", removed)
+		fmt.Fprintf(w, "banned %v: %d nodes removed\n", addrPort.Addr(), removed)
+	})
+
+	handle(api.AdminUnbanPath, func(w http.ResponseWriter, r *http.Request) {
+		addrPort, err := adminHost(r, defaultPort)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		banned, err := amgr.Unban(addrPort.Addr())
+		if err != nil {
+			log.Error("Failed to save bans", "err", err)
+			http.Error(w, "failed to save bans", http.StatusInternalServerError)
+			return
+		}
+		if !banned {
+			http.Error(w, "not banned", http.StatusNotFound)
+			return
+		}
+		log.Info("Unbanned", "addr", addrPort.Addr())
+		fmt.Fprintf(w, "unbanned %v\n", addrPort.Addr())
+	})
+
+	handle(api.AdminPinPath, func(w http.ResponseWriter, r *http.Request) {
+		addrPort, err := adminHost(r, defaultPort)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !amgr.Pin(addrPort) {
+			http.Error(w, "host may not be added", http.StatusBadRequest)
+			return
+		}
+		log.Info("Pinned", "node", addrPort)
+		fmt.Fprintf(w, "pinned %v\n", addrPort)
+	})
+
+	handle(api.AdminUnpinPath, func(w http.ResponseWriter, r *http.Request) {
+		addrPort, err := adminHost(r, defaultPort)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !amgr.Unpin(addrPort) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Info("Unpinned", "node", addrPort)
+		fmt.Fprintf(w, "unpinned %v\n", addrPort)
+	})
+
+	// updateRanges returns a handler adding the range of the request to or
+	// removing it from the allowed or denied ranges with update.
+	updateRanges := func(verb string, add bool,
+		update func(netip.Prefix) (bool, error)) http.HandlerFunc {
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			prefix, err := adminRange(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			changed, err := update(prefix)
+			if err != nil {
+				log.Error("Failed to save ranges", "err", err)
+				http.Error(w, "failed to save ranges",
+					http.StatusInternalServerError)
+				return
+			}
+			if !changed && !add {
+				http.NotFound(w, r)
+				return
+			}
+			if changed {
+				log.Info("Updated ranges", "op", verb, "range", prefix)
+			}
+			fmt.Fprintf(w, "%s %v\n", verb, prefix)
+		}
+	}
+	handle(api.AdminAllowPath, updateRanges("allowed", true, amgr.Allow))
+	handle(api.AdminUnallowPath, updateRanges("unallowed", false, amgr.Unallow))
+	handle(api.AdminDenyPath, updateRanges("denied", true, amgr.Deny))
+	handle(api.AdminUndenyPath, updateRanges("undenied", false, amgr.Undeny))
+
+	// The ranges are only read, so unlike the other admin requests they are
+	// requested with GET.
+	mux.HandleFunc(api.AdminRangesPath, auth.require(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", appName)
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err := json.NewEncoder(w).Encode(amgr.Ranges())
+		if err != nil {
+			log.Warn("ranges: Encode failed", "err", err)
+		}
+	}))
+
+	handle(api.AdminPrunePath, func(w http.ResponseWriter, _ *http.Request) {
+		amgr.prunePeers()
+		fmt.Fprintln(w, "pruned")
+	})
+
+	handle(api.AdminFlushPath, func(w http.ResponseWriter, _ *http.Request) {
+		amgr.savePeers()
+		fmt.Fprintln(w, "flushed")
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrseeder/api"
+)
+
+func Test_AdminHandlers(t *testing.T) {
+	const token = "secret"
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &netConfig{
+		HTTP:      httpConfig{APITokens: []string{token}},
+		netParams: chaincfg.MainNetParams(),
+	}
+	mux := newServeMux(cfg, amgr, log)
+
+	// The requests change the state of the address manager, so they are
+	// run in order.
+	adminTests := []struct {
+		name           string
+		method         string
+		target         string
+		token          string
+		expectedStatus int
+	}{
+		{"no credentials", "POST", "/admin/pin?host=8.8.8.8", "", http.StatusUnauthorized},
+		{"unknown token", "POST", "/admin/pin?host=8.8.8.8", "other", http.StatusUnauthorized},
+		{"get pin", "GET", "/admin/pin?host=8.8.8.8", token, http.StatusMethodNotAllowed},
+		{"get prune", "GET", "/admin/prune", token, http.StatusMethodNotAllowed},
+		{"pin invalid host", "POST", "/admin/pin?host=seed.example.org", token, http.StatusBadRequest},
+		{"pin unroutable host", "POST", "/admin/pin?host=10.0.0.1", token, http.StatusBadRequest},
+		{"pin", "POST", "/admin/pin?host=8.8.8.8", token, http.StatusOK},
+		{"pin with port", "POST", "/admin/pin?host=8.8.4.4:9108", token, http.StatusOK},
+		{"unpin", "POST", "/admin/unpin?host=8.8.4.4", token, http.StatusOK},
+		{"unpin unknown", "POST", "/admin/unpin?host=1.1.1.1", token, http.StatusNotFound},
+		{"prune", "POST", "/admin/prune", token, http.StatusOK},
+		{"ban invalid host", "POST", "/admin/ban?host=", token, http.StatusBadRequest},
+		{"ban", "POST", "/admin/ban?host=8.8.4.4", token, http.StatusOK},
+		{"pin banned", "POST", "/admin/pin?host=8.8.4.4", token, http.StatusBadRequest},
+		{"unban", "POST", "/admin/unban?host=8.8.4.4", token, http.StatusOK},
+		{"unban not banned", "POST", "/admin/unban?host=8.8.4.4", token, http.StatusNotFound},
+		{"allow invalid range", "POST", "/admin/allow?range=8.8.8.8/33", token, http.StatusBadRequest},
+		{"allow", "POST", "/admin/allow?range=8.8.0.0/16", token, http.StatusOK},
+		{"allow again", "POST", "/admin/allow?range=8.8.0.0/16", token, http.StatusOK},
+		{"deny", "POST", "/admin/deny?range=1.1.1.0/24", token, http.StatusOK},
+		{"deny other", "POST", "/admin/deny?range=1.0.0.0/24", token, http.StatusOK},
+		{"undeny", "POST", "/admin/undeny?range=1.0.0.0/24", token, http.StatusOK},
+		{"undeny not denied", "POST", "/admin/undeny?range=1.0.0.0/24", token, http.StatusNotFound},
+		{"unallow not allowed", "POST", "/admin/unallow?range=9.9.0.0/16", token, http.StatusNotFound},
+		{"post ranges", "POST", "/admin/ranges", token, http.StatusMethodNotAllowed},
+		{"ranges no credentials", "GET", "/admin/ranges", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range adminTests {
+		r := httptest.NewRequest(test.method, test.target, nil)
+		if test.token != "" {
+			r.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != test.expectedStatus {
+			t.Fatalf("%s: expected status %d, got %d", test.name,
+				test.expectedStatus, w.Code)
+		}
+	}
+
+	// The pinned node is kept by the prune, while the banned node was
+	// removed and is not added again until it is pinned once more.
+	if node, ok := amgr.nodes["8.8.8.8:9108"]; !ok || !node.Pinned {
+		t.Fatal("expected node 8.8.8.8:9108 to be pinned")
+	}
+	if _, ok := amgr.nodes["8.8.4.4:9108"]; ok {
+		t.Fatal("expected banned node 8.8.4.4:9108 to be removed")
+	}
+	if _, ok := amgr.bans[netip.MustParseAddr("8.8.4.4")]; ok {
+		t.Fatal("expected 8.8.4.4 to be unbanned")
+	}
+
+	r := httptest.NewRequest("GET", api.AdminRangesPath, nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var ranges api.AddrRanges
+	if err := json.NewDecoder(w.Body).Decode(&ranges); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ranges.Allow, []string{"8.8.0.0/16"}) {
+		t.Fatalf("expected allowed ranges [8.8.0.0/16], got %v", ranges.Allow)
+	}
+	if !reflect.DeepEqual(ranges.Deny, []string{"1.1.1.0/24"}) {
+		t.Fatalf("expected denied ranges [1.1.1.0/24], got %v", ranges.Deny)
+	}
+}
@@ -1,12 +1,147 @@
 package api
 
+import "time"
+
 const (
 	// GetAddrsPath is the URL path to fetch a list of public nodes
 	GetAddrsPath = "/api/addrs"
 
+	// GetAddrsV2Path is the URL path to fetch a list of public nodes with
+	// the richer NodeV2 records. It accepts the same query parameters as
+	// GetAddrsPath.
+	GetAddrsV2Path = "/api/v2/addrs"
+
+	// GetAddrsSignedPath is the URL path to fetch a SignedManifest of public
+	// nodes. It accepts the filter and limit query parameters of
+	// GetAddrsPath and is only served when the operator configured a
+	// signing key.
+	GetAddrsSignedPath = "/api/addrs/signed"
+
+	// NodeInfoPath is the URL path prefix to fetch the full record of a
+	// single node. It is followed by the host of the node, with the port
+	// being optional when it is the default port of the network.
+	NodeInfoPath = "/api/node/"
+
+	// StatsPath is the URL path to fetch aggregate statistics about the
+	// known nodes and the crawler.
+	StatsPath = "/api/stats"
+
+	// SubmitPath is the URL path to submit a candidate node for crawling.
+	// Submissions are POSTed as a JSON encoded Submission.
+	SubmitPath = "/api/submit"
+
+	// OpenAPIPath is the URL path serving the OpenAPI specification of the
+	// HTTP API.
+	OpenAPIPath = "/api/spec"
+
+	// Admin API paths. These only accept authenticated POST requests and
+	// take the host to act on from the Host query parameter where
+	// applicable. Bans apply to all ports of the IP of the host.
+	AdminBanPath   = "/admin/ban"
+	AdminUnbanPath = "/admin/unban"
+	AdminPinPath   = "/admin/pin"
+	AdminUnpinPath = "/admin/unpin"
+	AdminPrunePath = "/admin/prune"
+	AdminFlushPath = "/admin/flush"
+
+	// Admin API paths managing the ranges of addresses nodes are allowed
+	// from or denied, which take the range to act on from the Range query
+	// parameter. AdminRangesPath returns the current AddrRanges and, unlike
+	// the other admin paths, is requested with GET.
+	AdminAllowPath   = "/admin/allow"
+	AdminUnallowPath = "/admin/unallow"
+	AdminDenyPath    = "/admin/deny"
+	AdminUndenyPath  = "/admin/undeny"
+	AdminRangesPath  = "/admin/ranges"
+
+	// HealthPath is the URL path reporting whether the process is up.
+	HealthPath = "/health"
+
+	// ReadyPath is the URL path reporting whether the seeder has enough
+	// fresh data to serve.
+	ReadyPath = "/ready"
+
 	IPVersion       = "ipversion"
 	ServiceFlag     = "services"
 	ProtocolVersion = "pver"
+
+	// MaxAge is the query parameter restricting the nodes to those
+	// successfully probed within the given number of minutes.
+	MaxAge = "maxage"
+
+	// Limit is the query parameter setting the maximum number of nodes to
+	// return. It is capped by the server.
+	Limit = "limit"
+
+	// Offset is the query parameter requesting a page of nodes starting at
+	// the given offset. Paged results are returned in a stable order rather
+	// than as a random selection, so that clients can page through all
+	// nodes.
+	Offset = "offset"
+
+	// Host is the query parameter specifying the node an admin request acts
+	// on.
+	Host = "host"
+
+	// Range is the query parameter specifying the CIDR range, such as
+	// 192.0.2.0/24, an admin request acts on. A single address is a range
+	// holding only that address.
+	Range = "range"
+
+	// Format is the query parameter selecting the response format of
+	// GetAddrsPath. It takes precedence over the Accept header.
+	Format = "format"
+
+	// Wait is the query parameter requesting a conditional request of
+	// GetAddrsPath which would be answered with 304 Not Modified to instead
+	// wait up to the given number of seconds for the set of nodes to change.
+	Wait = "wait"
+
+	// Sort is the query parameter selecting the best nodes by one of the
+	// Sort* orders instead of a random selection.
+	Sort = "sort"
+
+	// Fields is the query parameter selecting a comma separated list of
+	// node fields to return, named after their JSON keys. All fields are
+	// returned by default. It does not apply to the text format.
+	Fields = "fields"
+)
+
+// Response formats of GetAddrsPath.
+const (
+	// FormatNDJSON streams one JSON encoded Node per line. This is the
+	// default format.
+	FormatNDJSON = "ndjson"
+
+	// FormatJSON returns a single JSON array of Nodes.
+	FormatJSON = "json"
+
+	// FormatCSV returns the nodes as CSV with a header row naming the
+	// columns after the JSON fields.
+	FormatCSV = "csv"
+
+	// FormatText returns the host of one node per line. It is only selected
+	// by the Format query parameter.
+	FormatText = "text"
+
+	// FormatProtobuf returns a seederrpc.GetAddrsResponse encoded as a
+	// Protocol Buffers message. It is only supported by GetAddrsPath and
+	// ignores the Fields query parameter.
+	FormatProtobuf = "protobuf"
+)
+
+// Sort orders of GetAddrsPath.
+const (
+	// SortUptime orders nodes by the fraction of successful probes, highest
+	// first.
+	SortUptime = "uptime"
+
+	// SortLastSuccess orders nodes by the time of the last successful probe,
+	// most recent first.
+	SortLastSuccess = "lastsuccess"
+
+	// SortLatency orders nodes by handshake latency, lowest first.
+	SortLatency = "latency"
 )
 
 type Node struct {
@@ -14,3 +149,103 @@ type Node struct {
 	Services        uint64 `json:"services"`
 	ProtocolVersion uint32 `json:"pver"`
 }
+
+// NodeV2 is a public node as returned by GetAddrsV2Path.
+type NodeV2 struct {
+	Host            string    `json:"host"`
+	Services        uint64    `json:"services"`
+	ProtocolVersion uint32    `json:"pver"`
+	LastSeen        time.Time `json:"lastseen"`
+	LastSuccess     time.Time `json:"lastsuccess"`
+
+	// Uptime is the fraction of probes of the node which succeeded.
+	Uptime float64 `json:"uptime"`
+
+	UserAgent string `json:"useragent"`
+	Height    int64  `json:"height"`
+
+	// LatencyMS is the time the most recent successful probe took to
+	// complete the handshake with the node in milliseconds.
+	LatencyMS int64 `json:"latencyms"`
+}
+
+// NodeInfo is the full record of a single node.
+type NodeInfo struct {
+	Host            string    `json:"host"`
+	Services        uint64    `json:"services"`
+	ProtocolVersion uint32    `json:"pver"`
+	UserAgent       string    `json:"useragent"`
+	Height          int64     `json:"height"`
+	LastAttempt     time.Time `json:"lastattempt"`
+	FirstSuccess    time.Time `json:"firstsuccess"`
+	LastSuccess     time.Time `json:"lastsuccess"`
+	LastSeen        time.Time `json:"lastseen"`
+	Attempts        uint64    `json:"attempts"`
+	Successes       uint64    `json:"successes"`
+
+	// Reliability is the fraction of probes of the node which succeeded.
+	Reliability float64 `json:"reliability"`
+
+	// LatencyMS is the time the most recent successful probe took to
+	// complete the handshake with the node in milliseconds.
+	LatencyMS int64 `json:"latencyms"`
+
+	// AdvertisedAddr is the differing address the node advertised for
+	// itself, if any.
+	AdvertisedAddr string `json:"advertisedaddr,omitempty"`
+
+	// Good reports whether the node is currently served to clients.
+	Good bool `json:"good"`
+}
+
+// Stats are aggregate statistics about the known nodes and the crawler. The
+// breakdowns only include good nodes, which are the nodes served to clients.
+type Stats struct {
+	Nodes            int            `json:"nodes"`
+	GoodNodes        int            `json:"goodnodes"`
+	IPVersions       map[uint32]int `json:"ipversions"`
+	ProtocolVersions map[uint32]int `json:"pvers"`
+	Services         map[uint64]int `json:"services"`
+	UserAgents       map[string]int `json:"useragents"`
+	Crawl            CrawlStats     `json:"crawl"`
+
+	// Cache describes the response cache. It is only set when caching is
+	// enabled.
+	Cache *CacheStats `json:"cache,omitempty"`
+}
+
+// CacheStats describe the use of the response cache since startup.
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+}
+
+// CrawlStats describe the crawl cycles performed since startup.
+type CrawlStats struct {
+	Cycles uint64 `json:"cycles"`
+
+	// LastStart, LastDurationMS and LastProbed describe the most recently
+	// completed crawl cycle.
+	LastStart      time.Time `json:"laststart"`
+	LastDurationMS int64     `json:"lastdurationms"`
+	LastProbed     int       `json:"lastprobed"`
+}
+
+// AddrRanges are the CIDR ranges of addresses nodes are allowed from or
+// denied. Nodes in a denied range are never added, probed or served. When any
+// ranges are allowed, so are only the nodes within them. Ranges set by the
+// configuration cannot be removed with the admin API.
+type AddrRanges struct {
+	Allow       []string `json:"allow"`
+	Deny        []string `json:"deny"`
+	ConfigAllow []string `json:"configallow"`
+	ConfigDeny  []string `json:"configdeny"`
+}
+
+// Submission is a candidate node submitted for crawling.
+type Submission struct {
+	// Host is the address of the node. The port is optional when it is the
+	// default port of the network.
+	Host string `json:"host"`
+}
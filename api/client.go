@@ -0,0 +1,158 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultClientTimeout is the default timeout of a request to a single
+// seeder.
+const DefaultClientTimeout = 30 * time.Second
+
+// AddrsFilter selects the nodes returned by the seeder. Zero values do not
+// filter.
+type AddrsFilter struct {
+	// IPVersion is either 4 or 6.
+	IPVersion int
+
+	// Services are the service flags all returned nodes must provide.
+	Services uint64
+
+	// ProtocolVersion is the minimum protocol version of returned nodes.
+	ProtocolVersion uint32
+
+	// MaxAge restricts the nodes to those successfully probed by the seeder
+	// within the duration. It is rounded up to whole minutes.
+	MaxAge time.Duration
+
+	// Limit is the maximum number of nodes to return. It is capped by the
+	// seeder.
+	Limit int
+}
+
+// Client fetches nodes from the HTTP API of one or more seeders. Seeders are
+// tried in order until one of them responds successfully.
+type Client struct {
+	urls       []string
+	timeout    time.Duration
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the seeders at the passed base URLs, such as
+// "https://mainnet-seed.example.org". Each request to a seeder is aborted
+// after timeout, or DefaultClientTimeout when it is zero.
+func NewClient(urls []string, timeout time.Duration) *Client {
+	if timeout == 0 {
+		timeout = DefaultClientTimeout
+	}
+	trimmed := make([]string, 0, len(urls))
+	for _, u := range urls {
+		trimmed = append(trimmed, strings.TrimRight(u, "/"))
+	}
+	return &Client{
+		urls:       trimmed,
+		timeout:    timeout,
+		httpClient: &http.Client{},
+	}
+}
+
+// GetAddrs returns nodes matching filter, which may be nil.
+func (c *Client) GetAddrs(ctx context.Context, filter *AddrsFilter) ([]Node, error) {
+	return getNodes[Node](ctx, c, GetAddrsPath, filter)
+}
+
+// GetAddrsV2 returns the richer records of nodes matching filter, which may be
+// nil.
+func (c *Client) GetAddrsV2(ctx context.Context, filter *AddrsFilter) ([]NodeV2, error) {
+	return getNodes[NodeV2](ctx, c, GetAddrsV2Path, filter)
+}
+
+func getNodes[T any](ctx context.Context, c *Client, path string, filter *AddrsFilter) ([]T, error) {
+	if len(c.urls) == 0 {
+		return nil, errors.New("no seeder URLs")
+	}
+
+	query := make(url.Values)
+	query.Set(Format, FormatNDJSON)
+	if filter != nil {
+		if filter.IPVersion != 0 {
+			query.Set(IPVersion, strconv.Itoa(filter.IPVersion))
+		}
+		if filter.Services != 0 {
+			query.Set(ServiceFlag, strconv.FormatUint(filter.Services, 10))
+		}
+		if filter.ProtocolVersion != 0 {
+			query.Set(ProtocolVersion,
+				strconv.FormatUint(uint64(filter.ProtocolVersion), 10))
+		}
+		if filter.MaxAge > 0 {
+			minutes := (filter.MaxAge + time.Minute - 1) / time.Minute
+			query.Set(MaxAge, strconv.FormatInt(int64(minutes), 10))
+		}
+		if filter.Limit != 0 {
+			query.Set(Limit, strconv.Itoa(filter.Limit))
+		}
+	}
+	rawQuery := query.Encode()
+
+	var errs []error
+	for _, u := range c.urls {
+		nodes, err := fetchNodes[T](ctx, c, u+path+"?"+rawQuery)
+		if err == nil {
+			return nodes, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", u, err))
+
+		// Do not try further seeders once the caller gave up.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// fetchNodes requests the NDJSON encoded nodes at u.
+func fetchNodes[T any](ctx context.Context, c *Client, u string) ([]T, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var nodes []T
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var node T
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nodes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
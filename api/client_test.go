@@ -0,0 +1,63 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_ClientGetAddrs(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	var query string
+	seeder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != GetAddrsPath {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.RawQuery
+		fmt.Fprintln(w, `{"host":"8.8.8.8:9108","services":1,"pver":11}`)
+		fmt.Fprintln(w, `{"host":"[2001:4860::1]:9108","services":5,"pver":10}`)
+	}))
+	defer seeder.Close()
+
+	c := NewClient([]string{failing.URL, seeder.URL + "/"}, 0)
+	nodes, err := c.GetAddrs(context.Background(), &AddrsFilter{
+		IPVersion: 6,
+		Limit:     2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expectedQuery = "format=ndjson&ipversion=6&limit=2"
+	if query != expectedQuery {
+		t.Fatalf("expected query %q, got %q", expectedQuery, query)
+	}
+	expected := []Node{
+		{Host: "8.8.8.8:9108", Services: 1, ProtocolVersion: 11},
+		{Host: "[2001:4860::1]:9108", Services: 5, ProtocolVersion: 10},
+	}
+	if len(nodes) != len(expected) {
+		t.Fatalf("expected %d nodes, got %d", len(expected), len(nodes))
+	}
+	for i := range expected {
+		if nodes[i] != expected[i] {
+			t.Fatalf("expected node %+v, got %+v", expected[i], nodes[i])
+		}
+	}
+
+	c = NewClient([]string{failing.URL}, 0)
+	_, err = c.GetAddrs(context.Background(), nil)
+	if err == nil {
+		t.Fatal("expected error when all seeders fail")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Manifest is a list of nodes of a network at a point in time.
+type Manifest struct {
+	// Network is the name of the network of the nodes, e.g. "mainnet".
+	Network   string    `json:"network"`
+	Timestamp time.Time `json:"timestamp"`
+	Nodes     []Node    `json:"nodes"`
+}
+
+// SignedManifest is a Manifest signed by the operator of a seeder as returned
+// by GetAddrsSignedPath.
+type SignedManifest struct {
+	// Manifest is the JSON encoded Manifest exactly as signed.
+	Manifest json.RawMessage `json:"manifest"`
+
+	// Signature is the hex encoded Ed25519 signature of Manifest.
+	Signature string `json:"signature"`
+
+	// PublicKey is the hex encoded Ed25519 public key of the operator. It is
+	// informational only; clients must verify the signature with a key they
+	// obtained from the operator out of band.
+	PublicKey string `json:"publickey"`
+}
+
+// Verify checks the signature of the manifest against the trusted public key
+// pubKey and returns the decoded manifest.
+func (s *SignedManifest) Verify(pubKey ed25519.PublicKey) (*Manifest, error) {
+	sig, err := hex.DecodeString(s.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(pubKey, s.Manifest, sig) {
+		return nil, errors.New("invalid manifest signature")
+	}
+	var manifest Manifest
+	err = json.Unmarshal(s.Manifest, &manifest)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func Test_SignedManifestVerify(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := Manifest{
+		Network:   "mainnet",
+		Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Nodes:     []Node{{Host: "8.8.8.8:9108", Services: 1, ProtocolVersion: 11}},
+	}
+	b, err := json.Marshal(&manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := SignedManifest{
+		Manifest:  b,
+		Signature: hex.EncodeToString(ed25519.Sign(key, b)),
+	}
+
+	// Round trip the signed manifest as a client would receive it.
+	b, err = json.Marshal(&signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received SignedManifest
+	err = json.Unmarshal(b, &received)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified, err := received.Verify(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Network != manifest.Network ||
+		!verified.Timestamp.Equal(manifest.Timestamp) ||
+		len(verified.Nodes) != 1 || verified.Nodes[0] != manifest.Nodes[0] {
+
+		t.Fatalf("expected manifest %+v, got %+v", manifest, *verified)
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := received.Verify(otherPub); err == nil {
+		t.Fatal("expected verification with another key to fail")
+	}
+}
@@ -0,0 +1,8 @@
+#!/bin/sh
+
+# Regenerates the Go code for the gRPC seed service. Requires protoc,
+# protoc-gen-go and protoc-gen-go-grpc.
+
+protoc -I. seeder.proto \
+	--go_out=. --go_opt=paths=source_relative \
+	--go-grpc_out=. --go-grpc_opt=paths=source_relative
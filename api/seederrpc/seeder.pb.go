@@ -0,0 +1,413 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: seeder.proto
+
+package seederrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AddrFilter restricts the returned nodes. Unset fields do not filter.
+type AddrFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IP version of the node addresses, either 4 or 6.
+	IpVersion uint32 `protobuf:"varint,1,opt,name=ip_version,json=ipVersion,proto3" json:"ip_version,omitempty"`
+	// Minimum protocol version of the nodes.
+	ProtocolVersion uint32 `protobuf:"varint,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// Services all nodes must provide.
+	Services uint64 `protobuf:"varint,3,opt,name=services,proto3" json:"services,omitempty"`
+	// Maximum number of nodes to return. It is capped by the server.
+	Limit uint32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *AddrFilter) Reset() {
+	*x = AddrFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_seeder_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddrFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddrFilter) ProtoMessage() {}
+
+func (x *AddrFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_seeder_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddrFilter.ProtoReflect.Descriptor instead.
+func (*AddrFilter) Descriptor() ([]byte, []int) {
+	return file_seeder_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddrFilter) GetIpVersion() uint32 {
+	if x != nil {
+		return x.IpVersion
+	}
+	return 0
+}
+
+func (x *AddrFilter) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *AddrFilter) GetServices() uint64 {
+	if x != nil {
+		return x.Services
+	}
+	return 0
+}
+
+func (x *AddrFilter) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetAddrsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *AddrFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *GetAddrsRequest) Reset() {
+	*x = GetAddrsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_seeder_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAddrsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddrsRequest) ProtoMessage() {}
+
+func (x *GetAddrsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_seeder_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddrsRequest.ProtoReflect.Descriptor instead.
+func (*GetAddrsRequest) Descriptor() ([]byte, []int) {
+	return file_seeder_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAddrsRequest) GetFilter() *AddrFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// Node is a reliable node on the network.
+type Node struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Address and port of the node.
+	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// Services provided by the node.
+	Services uint64 `protobuf:"varint,2,opt,name=services,proto3" json:"services,omitempty"`
+	// Protocol version of the node.
+	ProtocolVersion uint32 `protobuf:"varint,3,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_seeder_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_seeder_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_seeder_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Node) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Node) GetServices() uint64 {
+	if x != nil {
+		return x.Services
+	}
+	return 0
+}
+
+func (x *Node) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+type GetAddrsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Nodes []*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+}
+
+func (x *GetAddrsResponse) Reset() {
+	*x = GetAddrsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_seeder_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAddrsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAddrsResponse) ProtoMessage() {}
+
+func (x *GetAddrsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_seeder_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAddrsResponse.ProtoReflect.Descriptor instead.
+func (*GetAddrsResponse) Descriptor() ([]byte, []int) {
+	return file_seeder_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAddrsResponse) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+var File_seeder_proto protoreflect.FileDescriptor
+
+var file_seeder_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x22, 0x88, 0x01, 0x0a, 0x0a, 0x41, 0x64,
+	0x64, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x69, 0x70,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x22, 0x40, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x61, 0x0a, 0x04, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f,
+	0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x29,
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x39, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x41, 0x64, 0x64, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a,
+	0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x73,
+	0x65, 0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x6e,
+	0x6f, 0x64, 0x65, 0x73, 0x32, 0x96, 0x01, 0x0a, 0x06, 0x53, 0x65, 0x65, 0x64, 0x65, 0x72, 0x12,
+	0x43, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1a, 0x2e, 0x73, 0x65,
+	0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41, 0x64, 0x64,
+	0x72, 0x73, 0x12, 0x1a, 0x2e, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
+	0x2e, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64,
+	0x64, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x2b, 0x5a,
+	0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x65, 0x63, 0x72,
+	0x65, 0x64, 0x2f, 0x64, 0x63, 0x72, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x73, 0x65, 0x65, 0x64, 0x65, 0x72, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_seeder_proto_rawDescOnce sync.Once
+	file_seeder_proto_rawDescData = file_seeder_proto_rawDesc
+)
+
+func file_seeder_proto_rawDescGZIP() []byte {
+	file_seeder_proto_rawDescOnce.Do(func() {
+		file_seeder_proto_rawDescData = protoimpl.X.CompressGZIP(file_seeder_proto_rawDescData)
+	})
+	return file_seeder_proto_rawDescData
+}
+
+var file_seeder_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_seeder_proto_goTypes = []any{
+	(*AddrFilter)(nil),       // 0: seederrpc.AddrFilter
+	(*GetAddrsRequest)(nil),  // 1: seederrpc.GetAddrsRequest
+	(*Node)(nil),             // 2: seederrpc.Node
+	(*GetAddrsResponse)(nil), // 3: seederrpc.GetAddrsResponse
+}
+var file_seeder_proto_depIdxs = []int32{
+	0, // 0: seederrpc.GetAddrsRequest.filter:type_name -> seederrpc.AddrFilter
+	2, // 1: seederrpc.GetAddrsResponse.nodes:type_name -> seederrpc.Node
+	1, // 2: seederrpc.Seeder.GetAddrs:input_type -> seederrpc.GetAddrsRequest
+	1, // 3: seederrpc.Seeder.WatchAddrs:input_type -> seederrpc.GetAddrsRequest
+	3, // 4: seederrpc.Seeder.GetAddrs:output_type -> seederrpc.GetAddrsResponse
+	3, // 5: seederrpc.Seeder.WatchAddrs:output_type -> seederrpc.GetAddrsResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_seeder_proto_init() }
+func file_seeder_proto_init() {
+	if File_seeder_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_seeder_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*AddrFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_seeder_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAddrsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_seeder_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Node); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_seeder_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*GetAddrsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_seeder_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_seeder_proto_goTypes,
+		DependencyIndexes: file_seeder_proto_depIdxs,
+		MessageInfos:      file_seeder_proto_msgTypes,
+	}.Build()
+	File_seeder_proto = out.File
+	file_seeder_proto_rawDesc = nil
+	file_seeder_proto_goTypes = nil
+	file_seeder_proto_depIdxs = nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: seeder.proto
+
+package seederrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	Seeder_GetAddrs_FullMethodName   = "/seederrpc.Seeder/GetAddrs"
+	Seeder_WatchAddrs_FullMethodName = "/seederrpc.Seeder/WatchAddrs"
+)
+
+// SeederClient is the client API for Seeder service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Seeder provides the reliable nodes known to dcrseeder for a single network.
+type SeederClient interface {
+	// GetAddrs returns a selection of reliable nodes matching the filter.
+	GetAddrs(ctx context.Context, in *GetAddrsRequest, opts ...grpc.CallOption) (*GetAddrsResponse, error)
+	// WatchAddrs returns a selection of reliable nodes matching the filter
+	// immediately and streams a new selection whenever the set of reliable
+	// nodes changes.
+	WatchAddrs(ctx context.Context, in *GetAddrsRequest, opts ...grpc.CallOption) (Seeder_WatchAddrsClient, error)
+}
+
+type seederClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSeederClient(cc grpc.ClientConnInterface) SeederClient {
+	return &seederClient{cc}
+}
+
+func (c *seederClient) GetAddrs(ctx context.Context, in *GetAddrsRequest, opts ...grpc.CallOption) (*GetAddrsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAddrsResponse)
+	err := c.cc.Invoke(ctx, Seeder_GetAddrs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *seederClient) WatchAddrs(ctx context.Context, in *GetAddrsRequest, opts ...grpc.CallOption) (Seeder_WatchAddrsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Seeder_ServiceDesc.Streams[0], Seeder_WatchAddrs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &seederWatchAddrsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Seeder_WatchAddrsClient interface {
+	Recv() (*GetAddrsResponse, error)
+	grpc.ClientStream
+}
+
+type seederWatchAddrsClient struct {
+	grpc.ClientStream
+}
+
+func (x *seederWatchAddrsClient) Recv() (*GetAddrsResponse, error) {
+	m := new(GetAddrsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SeederServer is the server API for Seeder service.
+// All implementations must embed UnimplementedSeederServer
+// for forward compatibility
+//
+// Seeder provides the reliable nodes known to dcrseeder for a single network.
+type SeederServer interface {
+	// GetAddrs returns a selection of reliable nodes matching the filter.
+	GetAddrs(context.Context, *GetAddrsRequest) (*GetAddrsResponse, error)
+	// WatchAddrs returns a selection of reliable nodes matching the filter
+	// immediately and streams a new selection whenever the set of reliable
+	// nodes changes.
+	WatchAddrs(*GetAddrsRequest, Seeder_WatchAddrsServer) error
+	mustEmbedUnimplementedSeederServer()
+}
+
+// UnimplementedSeederServer must be embedded to have forward compatible implementations.
+type UnimplementedSeederServer struct {
+}
+
+func (UnimplementedSeederServer) GetAddrs(context.Context, *GetAddrsRequest) (*GetAddrsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAddrs not implemented")
+}
+func (UnimplementedSeederServer) WatchAddrs(*GetAddrsRequest, Seeder_WatchAddrsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAddrs not implemented")
+}
+func (UnimplementedSeederServer) mustEmbedUnimplementedSeederServer() {}
+
+// UnsafeSeederServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SeederServer will
+// result in compilation errors.
+type UnsafeSeederServer interface {
+	mustEmbedUnimplementedSeederServer()
+}
+
+func RegisterSeederServer(s grpc.ServiceRegistrar, srv SeederServer) {
+	s.RegisterService(&Seeder_ServiceDesc, srv)
+}
+
+func _Seeder_GetAddrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAddrsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SeederServer).GetAddrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Seeder_GetAddrs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SeederServer).GetAddrs(ctx, req.(*GetAddrsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Seeder_WatchAddrs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetAddrsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SeederServer).WatchAddrs(m, &seederWatchAddrsServer{ServerStream: stream})
+}
+
+type Seeder_WatchAddrsServer interface {
+	Send(*GetAddrsResponse) error
+	grpc.ServerStream
+}
+
+type seederWatchAddrsServer struct {
+	grpc.ServerStream
+}
+
+func (x *seederWatchAddrsServer) Send(m *GetAddrsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Seeder_ServiceDesc is the grpc.ServiceDesc for Seeder service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Seeder_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "seederrpc.Seeder",
+	HandlerType: (*SeederServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAddrs",
+			Handler:    _Seeder_GetAddrs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAddrs",
+			Handler:       _Seeder_WatchAddrs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "seeder.proto",
+}
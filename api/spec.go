@@ -0,0 +1,13 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package api
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI 3 specification of the HTTP API in JSON. It is
+// served at OpenAPIPath.
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
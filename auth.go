@@ -0,0 +1,86 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// authorizer authenticates requests to privileged routes, such as the admin
+// API, using either HTTP basic authentication with the admin credentials or a
+// bearer token. Public routes do not use it.
+//
+// Only hashes of the secrets are compared so the comparison time does not
+// depend on their lengths.
+type authorizer struct {
+	basic  bool
+	user   [sha256.Size]byte
+	pass   [sha256.Size]byte
+	tokens [][sha256.Size]byte
+}
+
+func newAuthorizer(cfg *httpConfig) *authorizer {
+	a := &authorizer{
+		basic: cfg.AdminUser != "",
+		user:  sha256.Sum256([]byte(cfg.AdminUser)),
+		pass:  sha256.Sum256([]byte(cfg.AdminPass)),
+	}
+	for _, token := range cfg.APITokens {
+		a.tokens = append(a.tokens, sha256.Sum256([]byte(token)))
+	}
+	return a
+}
+
+// enabled returns whether any means of authentication is configured.
+// Privileged routes are disabled otherwise.
+func (a *authorizer) enabled() bool {
+	return a.basic || len(a.tokens) > 0
+}
+
+// authorized returns whether r carries valid credentials.
+func (a *authorizer) authorized(r *http.Request) bool {
+	if a.basic {
+		if user, pass, ok := r.BasicAuth(); ok {
+			gotUser := sha256.Sum256([]byte(user))
+			gotPass := sha256.Sum256([]byte(pass))
+			userOK := subtle.ConstantTimeCompare(gotUser[:], a.user[:])
+			passOK := subtle.ConstantTimeCompare(gotPass[:], a.pass[:])
+			return userOK&passOK == 1
+		}
+	}
+
+	const bearer = "Bearer "
+	auth := r.Header.Get("Authorization")
+	if len(auth) <= len(bearer) || !strings.EqualFold(auth[:len(bearer)], bearer) {
+		return false
+	}
+	got := sha256.Sum256([]byte(auth[len(bearer):]))
+	var ok int
+	for i := range a.tokens {
+		ok |= subtle.ConstantTimeCompare(got[:], a.tokens[i][:])
+	}
+	return ok == 1
+}
+
+// require wraps a handler of a privileged route to reject requests without
+// valid credentials.
+func (a *authorizer) require(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.authorized(r) {
+			w.Header().Set("Server", appName)
+			if a.basic {
+				w.Header().Set("WWW-Authenticate", `Basic realm="dcrseeder"`)
+			} else {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrseeder/api"
+)
+
+func Test_Authorizer(t *testing.T) {
+	basicCfg := &httpConfig{AdminUser: "admin", AdminPass: "pass"}
+	tokenCfg := &httpConfig{APITokens: []string{"token1", "token2"}}
+	bothCfg := &httpConfig{AdminUser: "admin", AdminPass: "pass",
+		APITokens: []string{"token1"}}
+
+	authTests := map[string]struct {
+		cfg                *httpConfig
+		user, pass         string
+		authorization      string
+		expectedAuthorized bool
+		expectedChallenge  string
+	}{
+		"valid credentials": {basicCfg, "admin", "pass", "", true, ""},
+		"wrong password":    {basicCfg, "admin", "wrong", "", false, `Basic realm="dcrseeder"`},
+		"wrong user":        {basicCfg, "root", "pass", "", false, `Basic realm="dcrseeder"`},
+		"no credentials":    {basicCfg, "", "", "", false, `Basic realm="dcrseeder"`},
+		"token":             {tokenCfg, "", "", "Bearer token2", true, ""},
+		"token scheme case": {tokenCfg, "", "", "bearer token1", true, ""},
+		"unknown token":     {tokenCfg, "", "", "Bearer token3", false, "Bearer"},
+		"token prefix":      {tokenCfg, "", "", "Bearer token", false, "Bearer"},
+		"empty token":       {tokenCfg, "", "", "Bearer ", false, "Bearer"},
+		"basic without user": {
+			tokenCfg, "admin", "pass", "", false, "Bearer",
+		},
+		"token with basic configured": {
+			bothCfg, "", "", "Bearer token1", true, "",
+		},
+		"not configured": {
+			&httpConfig{}, "", "", "Bearer ", false, "Bearer",
+		},
+	}
+
+	for testName, test := range authTests {
+		auth := newAuthorizer(test.cfg)
+		r := httptest.NewRequest("POST", api.AdminPrunePath, nil)
+		if test.user != "" {
+			r.SetBasicAuth(test.user, test.pass)
+		}
+		if test.authorization != "" {
+			r.Header.Set("Authorization", test.authorization)
+		}
+		if auth.authorized(r) != test.expectedAuthorized {
+			t.Fatalf("%s: expected authorized %v", testName,
+				test.expectedAuthorized)
+		}
+
+		var called bool
+		w := httptest.NewRecorder()
+		auth.require(func(http.ResponseWriter, *http.Request) {
+			called = true
+		})(w, r)
+		if called != test.expectedAuthorized {
+			t.Fatalf("%s: expected handler called %v, got %v", testName,
+				test.expectedAuthorized, called)
+		}
+		if test.expectedAuthorized {
+			continue
+		}
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected status %d, got %d", testName,
+				http.StatusUnauthorized, w.Code)
+		}
+		challenge := w.Header().Get("WWW-Authenticate")
+		if challenge != test.expectedChallenge {
+			t.Fatalf("%s: expected challenge %q, got %q", testName,
+				test.expectedChallenge, challenge)
+		}
+	}
+}
+
+func Test_AdminNotConfigured(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &netConfig{netParams: chaincfg.MainNetParams()}
+	if newAuthorizer(&cfg.HTTP).enabled() {
+		t.Fatal("expected authorizer without credentials to be disabled")
+	}
+
+	// The admin API is not served at all without credentials, so even a
+	// request with an empty token does not reach it.
+	mux := newServeMux(cfg, amgr, log)
+	r := httptest.NewRequest("POST", api.AdminPrunePath, nil)
+	r.Header.Set("Authorization", "Bearer ")
+	if _, pattern := mux.Handler(r); pattern != "" {
+		t.Fatalf("expected admin API to be disabled, got route %q", pattern)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrseeder/api"
+)
+
+// maxCacheEntries is the maximum number of responses held by a responseCache.
+// Responses to further distinct requests are not cached until entries expire.
+const maxCacheEntries = 1024
+
+// cachedResponse is a successful response held by a responseCache.
+type cachedResponse struct {
+	expires time.Time
+	header  http.Header
+	body    []byte
+}
+
+// responseCache serves the responses of a handler from memory for a fixed
+// time after they were produced for an equivalent request.
+type responseCache struct {
+	ttl time.Duration
+
+	mtx     sync.Mutex
+	entries map[string]*cachedResponse
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]*cachedResponse),
+	}
+}
+
+// cacheKey returns the key of the cached response to r. Requests for the same
+// path with the same query parameters and response format are equivalent. The
+// requested format is sufficient for the key since the path identifies the
+// endpoint and the formats it serves.
+func cacheKey(r *http.Request) string {
+	return r.URL.Path + "?" + r.URL.Query().Encode() + "\x00" + addrsFormat(r, true)
+}
+
+func (c *responseCache) get(key string, now time.Time) *cachedResponse {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || now.After(entry.expires) {
+		return nil
+	}
+	return entry
+}
+
+func (c *responseCache) put(key string, entry *cachedResponse, now time.Time) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.entries) >= maxCacheEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = entry
+}
+
+// handler returns next with its successful responses cached. Cached responses
+// still honor conditional request headers, and long-polling requests are never
+// served from the cache.
+func (c *responseCache) handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Long-polling requests must observe the current set of nodes.
+		if r.URL.Query().Has(api.Wait) {
+			next(w, r)
+			return
+		}
+
+		key := cacheKey(r)
+		now := time.Now()
+		if entry := c.get(key, now); entry != nil {
+			c.hits.Add(1)
+			for k, v := range entry.header {
+				w.Header()[k] = v
+			}
+			etag := entry.header.Get("ETag")
+			modified, _ := http.ParseTime(entry.header.Get("Last-Modified"))
+			if etag != "" && notModified(r, etag, modified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(entry.body)
+			return
+		}
+		c.misses.Add(1)
+
+		rec := &cacheRecorder{header: make(http.Header)}
+		next(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		if rec.status == http.StatusOK {
+			c.put(key, &cachedResponse{
+				expires: now.Add(c.ttl),
+				header:  rec.header.Clone(),
+				body:    rec.body.Bytes(),
+			}, now)
+		}
+
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	}
+}
+
+// stats returns the cache statistics reported by the stats API.
+func (c *responseCache) stats() *api.CacheStats {
+	c.mtx.Lock()
+	entries := len(c.entries)
+	c.mtx.Unlock()
+	return &api.CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: entries,
+	}
+}
+
+// cacheRecorder is a http.ResponseWriter buffering a response so that it can
+// be cached.
+type cacheRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *cacheRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *cacheRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *cacheRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// Flush is a no-op so that streaming handlers can write to a cacheRecorder.
+func (r *cacheRecorder) Flush() {}
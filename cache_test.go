@@ -0,0 +1,68 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_ResponseCache(t *testing.T) {
+	var calls int
+	cache := newResponseCache(time.Hour)
+	handler := cache.handler(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("ETag", `W/"tag"`)
+		fmt.Fprintf(w, "response %d", calls)
+	})
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	cacheTests := []struct {
+		target         string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/api/addrs?limit=2", "", http.StatusOK, "response 1"},
+		{"/api/addrs?limit=2", "", http.StatusOK, "response 1"},
+		{"/api/addrs?limit=2", `W/"tag"`, http.StatusNotModified, ""},
+		{"/api/addrs?limit=3", "", http.StatusOK, "response 2"},
+		{"/api/addrs?limit=2&format=json", "", http.StatusOK, "response 3"},
+		{"/api/addrs?fail=1", "", http.StatusInternalServerError, "failed\n"},
+		{"/api/addrs?fail=1", "", http.StatusInternalServerError, "failed\n"},
+	}
+	for i, test := range cacheTests {
+		w := get(test.target, test.ifNoneMatch)
+		if w.Code != test.expectedStatus {
+			t.Fatalf("%d: expected status %d, got %d", i,
+				test.expectedStatus, w.Code)
+		}
+		if w.Body.String() != test.expectedBody {
+			t.Fatalf("%d: expected body %q, got %q", i, test.expectedBody,
+				w.Body.String())
+		}
+	}
+
+	stats := cache.stats()
+	if stats.Hits != 2 || stats.Misses != 5 || stats.Entries != 3 {
+		t.Fatalf("unexpected stats %+v", *stats)
+	}
+}
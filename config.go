@@ -5,13 +5,21 @@
 package main
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"net/netip"
 	"os"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
@@ -21,7 +29,17 @@ import (
 const (
 	appName               = "dcrseeder"
 	defaultConfigFilename = appName + ".conf"
+	defaultConfDirname    = "conf.d"
 	defaultHTTPPort       = "8000"
+	defaultGRPCPort       = "8100"
+
+	// defaultTorExitList is the URL of the list of Tor exit node addresses
+	// published by the Tor Project.
+	defaultTorExitList = "https://check.torproject.org/torbulkexitlist"
+
+	// minAPITokenLen is the minimum length of bearer tokens authorizing
+	// privileged routes.
+	minAPITokenLen = 16
 )
 
 var (
@@ -34,22 +52,141 @@ var (
 //
 // See loadConfig for details on the configuration load process.
 type config struct {
+	ConfigFile       string        `short:"C" long:"configfile" description:"Path to configuration file"`
+	ConfDir          string        `long:"confdir" description:"Directory of config file fragments merged into the configuration in lexical order (default: conf.d next to the config file)"`
+	AppData          string        `short:"A" long:"appdata" description:"Path to application home directory holding the configuration and the network data directories"`
+	Validate         bool          `long:"validate" description:"Check the configuration, listener addresses, key files and data directories, print a report and exit without starting any services"`
+	CrawlOnly        bool          `long:"crawlonly" description:"Only crawl the networks and maintain the node databases; do not start any servers"`
+	LogLevel         string        `long:"loglevel" default:"info" description:"Logging level for all subsystems {debug, info, warn, error}, optionally followed by subsystem=level pairs, e.g. info,crawl=debug; subsystems are main, amgr, crawl, p2p, http and grpc"`
+	LogBackend       string        `long:"logbackend" default:"stdout" choice:"stdout" choice:"syslog" choice:"journald" description:"Destination of log records; journald writes to stderr with priority prefixes"`
+	Unroutable       []string      `long:"unroutable" description:"Additional CIDR range whose nodes are treated as unroutable on all networks; may be specified multiple times"`
+	UnroutableFile   string        `long:"unroutablefile" description:"File listing additional CIDR ranges treated as unroutable, one per line, such as a bogon list"`
+	TorExits         bool          `long:"torexits" description:"Fetch the list of Tor exit node addresses and never mark nodes at them as good"`
+	TorExitList      string        `long:"torexitlist" description:"URL of the Tor exit list, with one IP address per line"`
+	TorExitRefresh   time.Duration `long:"torexitrefresh" default:"1h" description:"Interval at which the Tor exit list is fetched again"`
+	HTTPListen       string        `long:"httplisten" description:"HTTP listen on address:port for all enabled networks, routed by the /mainnet/, /testnet/ and /simnet/ path prefixes; always serves plain HTTP"`
+	UserAgentName    string        `long:"useragentname" description:"User agent name advertised to peers"`
+	UserAgentVersion string        `long:"useragentversion" description:"User agent version advertised to peers"`
+
+	logLevels *logLevels
+
+	// configFiles are the config files and fragments which were read.
+	configFiles []string
+
 	Mainnet *netConfig `group:"Mainnet" namespace:"mainnet"`
 	Testnet *netConfig `group:"Testnet" namespace:"testnet"`
+	Simnet  *netConfig `group:"Simnet" namespace:"simnet"`
 }
 
 type netConfig struct {
-	Enabled bool   `long:"enabled" description:"Enable dcrseeder on this network"`
-	Listen  string `long:"listen" description:"HTTP listen on address:port (must be unique per network)"`
-	Seeder  string `long:"seeder" description:"IP address of a working node on this network"`
+	Enabled bool     `long:"enabled" description:"Enable dcrseeder on this network"`
+	Listen  []string `long:"listen" description:"HTTP listen on address:port; may be specified multiple times to listen on several interfaces (must be unique per network)"`
+	Seeder  string   `long:"seeder" description:"IP address of a working node on this network"`
+
+	P2PListen  string `long:"p2plisten" description:"Accept inbound P2P connections on address:port and record the addresses they gossip"`
+	GRPCListen string `long:"grpclisten" description:"gRPC listen on address:port (must be unique per network)"`
+	P2PPort    uint16 `long:"p2pport" description:"P2P port assumed for nodes of this network, overriding the default port of its chain parameters"`
+	DataDir    string `long:"datadir" description:"Directory holding the node database of this network (default: <appdata>/<network name>)"`
+	LogFile    string `long:"logfile" description:"Write the log records of this network to this file instead of the log backend"`
+	ParamsFile string `long:"paramsfile" description:"JSON file with custom chain parameters (name, net, defaultport) overriding those of this network"`
+
+	Allow []string `long:"allow" description:"Only accept and serve nodes within this CIDR range, e.g. 198.51.100.0/24; may be specified multiple times"`
+	Deny  []string `long:"deny" description:"Never accept or serve nodes within this CIDR range; may be specified multiple times"`
+
+	Crawl crawlConfig `group:"Crawl tuning" namespace:"crawl"`
+	HTTP  httpConfig  `group:"HTTP API" namespace:"http"`
+
+	// policy decides which nodes are accepted on the network. Its
+	// additional unroutable ranges are shared by all networks, and its
+	// allowed and denied ranges are parsed from Allow and Deny.
+	policy *routingPolicy
+
+	// name is the namespace of the network options, which also prefixes the
+	// paths of the network on the shared HTTP listener.
+	name string
 
 	netParams *chaincfg.Params
 	seederIP  netip.AddrPort
 	dataDir   string
 }
 
+// crawlConfig defines the options used to tune the crawler of a single
+// network. Small networks such as testnet behave very differently from mainnet,
+// so each network carries its own set.
+type crawlConfig struct {
+	MaxProbes    int           `long:"maxprobes" default:"16" description:"Maximum number of peers to probe concurrently"`
+	NodeTimeout  time.Duration `long:"nodetimeout" default:"3s" description:"Timeout on responses from a probed peer"`
+	StaleTimeout time.Duration `long:"staletimeout" default:"1h" description:"Time after which a node is considered stale and probed again"`
+	StableAge    time.Duration `long:"stableage" default:"1h" description:"Time since the first successful probe of a node before it is served"`
+	IdleTimeout  time.Duration `long:"idletimeout" default:"10m" description:"Time to wait for new addresses when there are no stale addresses to probe"`
+
+	// The user agent is shared by all networks.
+	userAgentName    string
+	userAgentVersion string
+}
+
+// httpConfig defines the options of the HTTP API of a single network.
+type httpConfig struct {
+	MaxAddrs          int           `long:"maxaddrs" default:"1000" description:"Maximum number of nodes returned by a single request"`
+	SubmitLimit       int           `long:"submitlimit" default:"0" description:"Maximum number of node submissions accepted per client IP per hour; submissions are disabled by default (0)"`
+	CacheTTL          time.Duration `long:"cachettl" default:"0s" description:"Time the responses to node list requests are cached for (0 disables caching)"`
+	TLSCert           string        `long:"tlscert" description:"File containing the certificate used to serve HTTPS"`
+	TLSKey            string        `long:"tlskey" description:"File containing the key of the certificate used to serve HTTPS"`
+	AutoCert          []string      `long:"autocert" description:"Serve HTTPS using certificates for this host name obtained automatically from Let's Encrypt; may be specified multiple times"`
+	AutoCertDir       string        `long:"autocertdir" description:"Directory caching automatically obtained certificates (default: <network data dir>/autocert)"`
+	AdminUser         string        `long:"adminuser" description:"Username authorizing privileged routes such as the admin API"`
+	AdminPass         string        `long:"adminpass" default-mask:"-" description:"Password authorizing privileged routes such as the admin API"`
+	APITokens         []string      `long:"apitoken" default-mask:"-" description:"Bearer token authorizing privileged routes such as the admin API; may be specified multiple times"`
+	ReadyMinNodes     int           `long:"readyminnodes" default:"1" description:"Minimum number of good nodes required to report ready"`
+	ReadyMaxAge       time.Duration `long:"readymaxage" default:"1h" description:"Maximum time since the last successful probe to report ready"`
+	ReadTimeout       time.Duration `long:"readtimeout" default:"10s" description:"Maximum time to read a request"`
+	ReadHeaderTimeout time.Duration `long:"readheadertimeout" default:"5s" description:"Maximum time to read the headers of a request"`
+	WriteTimeout      time.Duration `long:"writetimeout" default:"10s" description:"Maximum time from reading the headers of a request to writing the response"`
+	StreamTimeout     time.Duration `long:"streamtimeout" default:"5m" description:"Maximum time to write a node list, which may be large and is streamed to slow clients"`
+	IdleTimeout       time.Duration `long:"idletimeout" default:"2m" description:"Maximum time to wait for the next request on a keep-alive connection"`
+	MaxHeaderBytes    int           `long:"maxheaderbytes" default:"1048576" description:"Maximum size of the headers of a request"`
+	DisableHTTP2      bool          `long:"disablehttp2" description:"Only serve HTTP/1.1 over TLS"`
+	SigningKey        string        `long:"signingkey" description:"File containing the PKCS #8 PEM encoded Ed25519 key signing node manifests served by /api/addrs/signed"`
+
+	signingKey ed25519.PrivateKey
+}
+
 func loadConfig() (*config, error) {
-	err := os.MkdirAll(defaultHomeDir, 0o700)
+	// Default config.
+	cfg := config{
+		ConfigFile:       defaultConfigFile,
+		UserAgentName:    appName,
+		UserAgentVersion: Version,
+		TorExitList:      defaultTorExitList,
+	}
+
+	preCfg := cfg
+	preParser := flags.NewParser(&preCfg, flags.Default)
+	_, err := preParser.Parse()
+	if err != nil {
+		var e *flags.Error
+		if errors.As(err, &e) && e.Type == flags.ErrHelp {
+			os.Exit(0)
+		}
+		preParser.WriteHelp(os.Stderr)
+		return nil, err
+	}
+
+	// The config file is read from the relocated home directory unless it
+	// was specified explicitly.
+	usingDefaultConfig := preCfg.ConfigFile == defaultConfigFile
+	if !usingDefaultConfig {
+		preCfg.ConfigFile = cleanAndExpandPath(preCfg.ConfigFile)
+	}
+	homeDir := defaultHomeDir
+	if preCfg.AppData != "" {
+		homeDir = cleanAndExpandPath(preCfg.AppData)
+		if usingDefaultConfig {
+			preCfg.ConfigFile = filepath.Join(homeDir, defaultConfigFilename)
+		}
+	}
+
+	err = os.MkdirAll(homeDir, 0o700)
 	if err != nil {
 		// Show a nicer error message if it's because a symlink is
 		// linked to a directory that does not exist (probably because
@@ -65,31 +202,20 @@ func loadConfig() (*config, error) {
 		return nil, fmt.Errorf("failed to create home directory: %v", err)
 	}
 
-	// Default config.
-	cfg := config{}
-
-	preCfg := cfg
-	preParser := flags.NewParser(&preCfg, flags.Default)
-	_, err = preParser.Parse()
-	if err != nil {
-		var e *flags.Error
-		if errors.As(err, &e) && e.Type == flags.ErrHelp {
-			os.Exit(0)
-		}
-		preParser.WriteHelp(os.Stderr)
-		return nil, err
-	}
-
 	appName := filepath.Base(os.Args[0])
 	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
 	usageMessage := fmt.Sprintf("Use %s -h to show usage", appName)
 
-	// Load additional config from file.
+	// Load additional config from file, which may also be in the TOML or
+	// YAML format. A missing config file is only an error when it was
+	// specified explicitly.
 	parser := flags.NewParser(&cfg, flags.Default)
-	err = flags.NewIniParser(parser).ParseFile(defaultConfigFile)
-	if err != nil {
+	err = parseConfigFile(parser, preCfg.ConfigFile)
+	if err == nil {
+		cfg.configFiles = append(cfg.configFiles, preCfg.ConfigFile)
+	} else {
 		var e *os.PathError
-		if !errors.As(err, &e) {
+		if !errors.As(err, &e) || !usingDefaultConfig {
 			fmt.Fprintf(os.Stderr, "Error parsing config "+
 				"file: %v\n", err)
 			fmt.Fprintln(os.Stderr, usageMessage)
@@ -97,6 +223,34 @@ func loadConfig() (*config, error) {
 		}
 	}
 
+	// Merge the fragments of the drop-in directory. Options set by a
+	// fragment override those of the files read before it. The directory
+	// is only required to exist when it was specified explicitly.
+	confDir := filepath.Join(filepath.Dir(preCfg.ConfigFile), defaultConfDirname)
+	if preCfg.ConfDir != "" {
+		confDir = cleanAndExpandPath(preCfg.ConfDir)
+	}
+	fragments, err := configFragments(confDir)
+	if err != nil && (preCfg.ConfDir != "" || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, fmt.Errorf("failed to read config directory: %w", err)
+	}
+	for _, path := range fragments {
+		err = parseConfigFile(parser, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error parsing config "+
+				"file: %v\n", err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		cfg.configFiles = append(cfg.configFiles, path)
+	}
+
+	// The home directory holds the config file, so it may only be
+	// relocated on the command line.
+	if cfg.AppData != "" {
+		return nil, fmt.Errorf("appdata may only be set on the command line")
+	}
+
 	// Parse command line options again to ensure they take precedence.
 	_, err = parser.Parse()
 	if err != nil {
@@ -107,23 +261,106 @@ func loadConfig() (*config, error) {
 		return nil, err
 	}
 
-	if !cfg.Mainnet.Enabled && !cfg.Testnet.Enabled {
+	if !cfg.Mainnet.Enabled && !cfg.Testnet.Enabled && !cfg.Simnet.Enabled {
 		return nil, fmt.Errorf("no networks enabled")
 	}
 
-	parseNet := func(cfg *netConfig, params *chaincfg.Params) error {
+	cfg.logLevels, err = parseLogLevels(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.logLevels.setBackend(cfg.LogBackend); err != nil {
+		return nil, err
+	}
+
+	// The user agent may not contain the characters used as delimiters by
+	// the wire protocol.
+	const uaReserved = "/:()"
+	if cfg.UserAgentName == "" || strings.ContainsAny(cfg.UserAgentName, uaReserved) {
+		return nil, fmt.Errorf("invalid user agent name %q", cfg.UserAgentName)
+	}
+	if cfg.UserAgentVersion == "" || strings.ContainsAny(cfg.UserAgentVersion, uaReserved) {
+		return nil, fmt.Errorf("invalid user agent version %q", cfg.UserAgentVersion)
+	}
+
+	var unroutable []netip.Prefix
+	for _, s := range cfg.Unroutable {
+		prefix, err := parsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid unroutable range: %w", err)
+		}
+		unroutable = append(unroutable, prefix)
+	}
+	if cfg.UnroutableFile != "" {
+		prefixes, err := loadPrefixFile(cleanAndExpandPath(cfg.UnroutableFile))
+		if err != nil {
+			return nil, fmt.Errorf("unroutablefile: %w", err)
+		}
+		unroutable = append(unroutable, prefixes...)
+	}
+
+	if cfg.TorExits && cfg.TorExitRefresh <= 0 {
+		return nil, fmt.Errorf("torexitrefresh must be positive")
+	}
+
+	crawlOnly := cfg.CrawlOnly
+	if crawlOnly {
+		cfg.HTTPListen = ""
+	} else if cfg.HTTPListen != "" {
+		cfg.HTTPListen = normalizeAddress(cfg.HTTPListen, defaultHTTPPort)
+	}
+	sharedListen := cfg.HTTPListen != ""
+	userAgentName, userAgentVersion := cfg.UserAgentName, cfg.UserAgentVersion
+	parseNet := func(cfg *netConfig, name string, params *chaincfg.Params) error {
 		// Only parse params for this network if it is enabled.
 		if !cfg.Enabled {
 			return nil
 		}
 
+		cfg.name = name
 		cfg.netParams = params
-		cfg.dataDir = filepath.Join(defaultHomeDir, cfg.netParams.Name)
+		if cfg.ParamsFile != "" {
+			cfg.ParamsFile = cleanAndExpandPath(cfg.ParamsFile)
+			cfg.netParams, err = loadNetParams(cfg.ParamsFile, params)
+			if err != nil {
+				return fmt.Errorf("paramsfile: %w", err)
+			}
+		}
+		if cfg.P2PPort != 0 {
+			params := *cfg.netParams
+			params.DefaultPort = strconv.Itoa(int(cfg.P2PPort))
+			cfg.netParams = &params
+		}
+		cfg.dataDir = filepath.Join(homeDir, cfg.netParams.Name)
+		if cfg.DataDir != "" {
+			cfg.dataDir = cleanAndExpandPath(cfg.DataDir)
+		}
+		if cfg.LogFile != "" {
+			cfg.LogFile = cleanAndExpandPath(cfg.LogFile)
+		}
+		for _, path := range []*string{&cfg.HTTP.TLSCert, &cfg.HTTP.TLSKey,
+			&cfg.HTTP.AutoCertDir, &cfg.HTTP.SigningKey} {
+			if *path != "" {
+				*path = cleanAndExpandPath(*path)
+			}
+		}
 
-		if cfg.Listen == "" {
+		// Listeners are not required when only crawling.
+		switch {
+		case crawlOnly:
+			cfg.Listen = nil
+			cfg.GRPCListen = ""
+		case len(cfg.Listen) == 0 && !sharedListen:
 			return fmt.Errorf("no listeners specified")
+		default:
+			for i, addr := range cfg.Listen {
+				cfg.Listen[i] = normalizeAddress(addr, defaultHTTPPort)
+			}
+			if cfg.GRPCListen != "" {
+				cfg.GRPCListen = normalizeAddress(cfg.GRPCListen,
+					defaultGRPCPort)
+			}
 		}
-		cfg.Listen = normalizeAddress(cfg.Listen, defaultHTTPPort)
 
 		if len(cfg.Seeder) == 0 {
 			return fmt.Errorf("no seeder specified")
@@ -135,22 +372,176 @@ func loadConfig() (*config, error) {
 			return fmt.Errorf("invalid seeder ip: %v", err)
 		}
 
+		if cfg.P2PListen != "" {
+			cfg.P2PListen = normalizeAddress(cfg.P2PListen,
+				cfg.netParams.DefaultPort)
+		}
+
+		if cfg.HTTP.MaxAddrs <= 0 {
+			return fmt.Errorf("http.maxaddrs must be positive")
+		}
+		if cfg.HTTP.SubmitLimit < 0 {
+			return fmt.Errorf("http.submitlimit may not be negative")
+		}
+		if cfg.HTTP.CacheTTL < 0 {
+			return fmt.Errorf("http.cachettl may not be negative")
+		}
+		if (cfg.HTTP.TLSCert == "") != (cfg.HTTP.TLSKey == "") {
+			return fmt.Errorf("http.tlscert and http.tlskey must be " +
+				"specified together")
+		}
+		if cfg.HTTP.TLSCert != "" && len(cfg.HTTP.AutoCert) > 0 {
+			return fmt.Errorf("http.tlscert and http.autocert may not " +
+				"be specified together")
+		}
+		if len(cfg.HTTP.AutoCert) > 0 && cfg.HTTP.AutoCertDir == "" {
+			cfg.HTTP.AutoCertDir = filepath.Join(cfg.dataDir, "autocert")
+		}
+
+		if cfg.HTTP.AdminUser != "" && cfg.HTTP.AdminPass == "" {
+			return fmt.Errorf("http.adminpass is required with http.adminuser")
+		}
+		for _, token := range cfg.HTTP.APITokens {
+			if len(token) < minAPITokenLen {
+				return fmt.Errorf("http.apitoken must be at least %d "+
+					"characters", minAPITokenLen)
+			}
+		}
+		if cfg.HTTP.ReadyMinNodes < 0 {
+			return fmt.Errorf("http.readyminnodes may not be negative")
+		}
+		if cfg.HTTP.ReadyMaxAge <= 0 {
+			return fmt.Errorf("http.readymaxage must be positive")
+		}
+		if cfg.HTTP.ReadTimeout <= 0 || cfg.HTTP.ReadHeaderTimeout <= 0 ||
+			cfg.HTTP.WriteTimeout <= 0 || cfg.HTTP.StreamTimeout <= 0 ||
+			cfg.HTTP.IdleTimeout <= 0 {
+
+			return fmt.Errorf("http timeouts must be positive")
+		}
+		if cfg.HTTP.MaxHeaderBytes <= 0 {
+			return fmt.Errorf("http.maxheaderbytes must be positive")
+		}
+		if cfg.HTTP.SigningKey != "" {
+			cfg.HTTP.signingKey, err = loadSigningKey(cfg.HTTP.SigningKey)
+			if err != nil {
+				return fmt.Errorf("http.signingkey: %w", err)
+			}
+		}
+
+		// Simnet clusters typically run on local addresses, which also
+		// applies to custom networks based on simnet.
+		cfg.policy = &routingPolicy{
+			acceptLocal: name == "simnet",
+			unroutable:  unroutable,
+		}
+		for _, s := range cfg.Allow {
+			prefix, err := parsePrefix(s)
+			if err != nil {
+				return fmt.Errorf("invalid allow range: %w", err)
+			}
+			cfg.policy.ranges.Allow = append(cfg.policy.ranges.Allow, prefix)
+		}
+		for _, s := range cfg.Deny {
+			prefix, err := parsePrefix(s)
+			if err != nil {
+				return fmt.Errorf("invalid deny range: %w", err)
+			}
+			cfg.policy.ranges.Deny = append(cfg.policy.ranges.Deny, prefix)
+		}
+		cfg.Crawl.userAgentName = userAgentName
+		cfg.Crawl.userAgentVersion = userAgentVersion
+
+		if cfg.Crawl.MaxProbes <= 0 {
+			return fmt.Errorf("crawl.maxprobes must be positive")
+		}
+		if cfg.Crawl.NodeTimeout <= 0 {
+			return fmt.Errorf("crawl.nodetimeout must be positive")
+		}
+		if cfg.Crawl.StaleTimeout <= 0 {
+			return fmt.Errorf("crawl.staletimeout must be positive")
+		}
+		if cfg.Crawl.StableAge < 0 {
+			return fmt.Errorf("crawl.stableage may not be negative")
+		}
+		if cfg.Crawl.IdleTimeout <= 0 {
+			return fmt.Errorf("crawl.idletimeout must be positive")
+		}
+
 		return nil
 	}
 
-	err = parseNet(cfg.Mainnet, chaincfg.MainNetParams())
+	err = parseNet(cfg.Mainnet, "mainnet", chaincfg.MainNetParams())
 	if err != nil {
 		return nil, fmt.Errorf("mainnet params error: %w", err)
 	}
 
-	err = parseNet(cfg.Testnet, chaincfg.TestNet3Params())
+	err = parseNet(cfg.Testnet, "testnet", chaincfg.TestNet3Params())
 	if err != nil {
 		return nil, fmt.Errorf("testnet params error: %w", err)
 	}
 
+	err = parseNet(cfg.Simnet, "simnet", chaincfg.SimNetParams())
+	if err != nil {
+		return nil, fmt.Errorf("simnet params error: %w", err)
+	}
+
 	return &cfg, nil
 }
 
+// loadSigningKey reads the PKCS #8 PEM encoded Ed25519 private key in the
+// file at path, such as one generated by "openssl genpkey -algorithm ed25519".
+func loadSigningKey(path string) (ed25519.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("no PEM encoded private key in %s", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an Ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// cleanAndExpandPath expands environment variables and a leading ~ or ~user in
+// path and cleans the result. The path is left unexpanded when the home
+// directory can not be determined.
+func cleanAndExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if !strings.HasPrefix(path, "~") {
+		return filepath.Clean(path)
+	}
+
+	// Both forward and backward slashes separate the user name on Windows.
+	separators := "/"
+	if runtime.GOOS == "windows" {
+		separators += `\`
+	}
+	userName, rest := path[1:], ""
+	if i := strings.IndexAny(userName, separators); i >= 0 {
+		userName, rest = userName[:i], userName[i:]
+	}
+
+	var home string
+	if userName == "" {
+		home, _ = os.UserHomeDir()
+	} else if u, err := user.Lookup(userName); err == nil {
+		home = u.HomeDir
+	}
+	if home == "" {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(home, rest)
+}
+
 // normalizeAddress returns addr with the passed default port appended if
 // there is not already a port specified.
 func normalizeAddress(addr, defaultPort string) string {
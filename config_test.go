@@ -0,0 +1,43 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func Test_CleanAndExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DCRSEEDER_TEST_DIR", "/srv/seeder")
+	current, err := user.Current()
+	if err != nil {
+		t.Skip("current user unknown:", err)
+	}
+
+	pathTests := map[string]struct {
+		path     string
+		expected string
+	}{
+		"absolute":        {"/var/lib/../lib/dcrseeder", "/var/lib/dcrseeder"},
+		"relative":        {"./dcrseeder.conf", "dcrseeder.conf"},
+		"home":            {"~", home},
+		"home subdir":     {"~/.dcrseeder/dcrseeder.conf", filepath.Join(home, ".dcrseeder/dcrseeder.conf")},
+		"user home":       {"~" + current.Username + "/conf", filepath.Join(current.HomeDir, "conf")},
+		"unknown user":    {"~nosuchuser-dcrseeder/conf", "~nosuchuser-dcrseeder/conf"},
+		"tilde not first": {"/srv/~/conf", "/srv/~/conf"},
+		"env":             {"$DCRSEEDER_TEST_DIR/conf", "/srv/seeder/conf"},
+	}
+
+	for testName, test := range pathTests {
+		actual := cleanAndExpandPath(test.path)
+		if actual != test.expected {
+			t.Fatalf("%s: expected %q, got %q", testName, test.expected,
+				actual)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	flags "github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// configFragmentExts are the extensions of the config file fragments read
+// from the drop-in directory. Other files, such as backups left by editors and
+// package managers, are ignored.
+var configFragmentExts = []string{".conf", ".ini", ".toml", ".yaml", ".yml"}
+
+// configFragments returns the paths of the config file fragments in dir in
+// lexical order. Hidden files and subdirectories are ignored.
+func configFragments(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !slices.Contains(configFragmentExts, strings.ToLower(filepath.Ext(name))) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths, nil
+}
+
+// configOption is a single option read from a TOML or YAML config file. Lists
+// are read as one option per element.
+type configOption struct {
+	name  string
+	value string
+}
+
+// parseConfigFile parses the config file at path into the options of parser.
+// TOML and YAML files, detected by their .toml, .yaml or .yml extension, are
+// converted to the INI format read by go-flags. Their tables or nested
+// mappings name the namespaces of the options, such as [mainnet.http] in TOML,
+// and lists set options which may be specified multiple times.
+func parseConfigFile(parser *flags.Parser, path string) error {
+	var decode func([]byte) (map[string]any, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		decode = decodeTOML
+	case ".yaml", ".yml":
+		decode = decodeYAML
+	default:
+		return flags.NewIniParser(parser).ParseFile(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	values, err := decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	opts, err := flattenConfig("", values)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Each option is written to its own line so that errors reported by the
+	// INI parser can be traced back to the option of the original file.
+	var ini strings.Builder
+	for _, opt := range opts {
+		ini.WriteString(opt.name + "=" + strconv.Quote(opt.value) + "\n")
+	}
+	err = flags.NewIniParser(parser).Parse(strings.NewReader(ini.String()))
+	var e *flags.IniError
+	if errors.As(err, &e) && e.LineNumber > 0 && int(e.LineNumber) <= len(opts) {
+		return fmt.Errorf("%s: %s: %s", path, opts[e.LineNumber-1].name,
+			e.Message)
+	}
+	return err
+}
+
+// decodeTOML decodes a TOML config file.
+func decodeTOML(data []byte) (map[string]any, error) {
+	var values map[string]any
+	if _, err := toml.Decode(string(data), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// decodeYAML decodes a YAML config file.
+func decodeYAML(data []byte) (map[string]any, error) {
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// flattenConfig converts the decoded values of a config file to options. The
+// keys of nested tables or mappings are joined with dots and prefixed by
+// namespace. Options are returned sorted by name, with the elements of lists
+// in order.
+func flattenConfig(namespace string, values map[string]any) ([]configOption, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var opts []configOption
+	for _, key := range keys {
+		name := key
+		if namespace != "" {
+			name = namespace + "." + key
+		}
+		switch v := values[key].(type) {
+		case map[string]any:
+			nested, err := flattenConfig(name, v)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, nested...)
+		case []any:
+			for _, elem := range v {
+				value, err := configValue(name, elem)
+				if err != nil {
+					return nil, err
+				}
+				opts = append(opts, configOption{name: name, value: value})
+			}
+		default:
+			value, err := configValue(name, v)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, configOption{name: name, value: value})
+		}
+	}
+	return opts, nil
+}
+
+// configValue formats the scalar value of the option name as it is written in
+// an INI file.
+func configValue(name string, v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", fmt.Errorf("%s: option has no value", name)
+	}
+	return "", fmt.Errorf("%s: unsupported value of type %T", name, v)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_ReadConfigFormats(t *testing.T) {
+	readTests := map[string]struct {
+		decode  func([]byte) (map[string]any, error)
+		in      string
+		opts    []configOption
+		wantErr bool
+	}{
+		"toml": {
+			decode: decodeTOML,
+			in: `# General settings
+loglevel = "info,crawl=debug"
+
+[mainnet]
+enabled = true
+"seeder" = '8.8.8.8' # comment
+listen = [
+  "127.0.0.1:8000",
+  "[::1]:8000",
+]
+p2pport = 0x2394
+
+[mainnet.http]
+maxaddrs = 1_000
+apitoken = "with # hash"
+`,
+			opts: []configOption{
+				{"loglevel", "info,crawl=debug"},
+				{"mainnet.enabled", "true"},
+				{"mainnet.http.apitoken", "with # hash"},
+				{"mainnet.http.maxaddrs", "1000"},
+				{"mainnet.listen", "127.0.0.1:8000"},
+				{"mainnet.listen", "[::1]:8000"},
+				{"mainnet.p2pport", "9108"},
+				{"mainnet.seeder", "8.8.8.8"},
+			},
+		},
+		"toml unquoted string": {
+			decode:  decodeTOML,
+			in:      "[mainnet]\nseeder = 8.8.8.8\n",
+			wantErr: true,
+		},
+		"toml array of tables": {
+			decode:  decodeTOML,
+			in:      "[[mainnet]]\nenabled = true\n",
+			wantErr: true,
+		},
+		"yaml": {
+			decode: decodeYAML,
+			in: `---
+loglevel: info
+mainnet:
+  enabled: true
+  seeder: 8.8.8.8 # comment
+  listen:
+  - 127.0.0.1:8000
+  - "[::1]:8000"
+  http:
+    maxaddrs: 1000
+    apitoken: 'it''s#secret'
+testnet: {enabled: false, listen: [127.0.0.1:8001, '[::1]:8001']}
+`,
+			opts: []configOption{
+				{"loglevel", "info"},
+				{"mainnet.enabled", "true"},
+				{"mainnet.http.apitoken", "it's#secret"},
+				{"mainnet.http.maxaddrs", "1000"},
+				{"mainnet.listen", "127.0.0.1:8000"},
+				{"mainnet.listen", "[::1]:8000"},
+				{"mainnet.seeder", "8.8.8.8"},
+				{"testnet.enabled", "false"},
+				{"testnet.listen", "127.0.0.1:8001"},
+				{"testnet.listen", "[::1]:8001"},
+			},
+		},
+		"yaml null": {
+			decode:  decodeYAML,
+			in:      "mainnet:\n  seeder: ~\n",
+			wantErr: true,
+		},
+		"yaml nested value": {
+			decode:  decodeYAML,
+			in:      "useragentname: foo: bar\n",
+			wantErr: true,
+		},
+		"yaml inconsistent indentation": {
+			decode:  decodeYAML,
+			in:      "mainnet:\n    enabled: true\n  seeder: 8.8.8.8\n",
+			wantErr: true,
+		},
+		"yaml list of lists": {
+			decode:  decodeYAML,
+			in:      "mainnet:\n  listen: [[127.0.0.1:8000]]\n",
+			wantErr: true,
+		},
+	}
+
+	for testName, test := range readTests {
+		values, err := test.decode([]byte(test.in))
+		var opts []configOption
+		if err == nil {
+			opts, err = flattenConfig("", values)
+		}
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", testName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", testName, err)
+		}
+		if !reflect.DeepEqual(opts, test.opts) {
+			t.Fatalf("%s: expected options %v, got %v", testName, test.opts,
+				opts)
+		}
+	}
+}
+
+func Test_ConfigFragments(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"20-http.toml", "10-listen.conf",
+		"30-net.yml", "20-http.toml~", ".10-hidden.conf", "README"} {
+
+		err := os.WriteFile(filepath.Join(dir, name), nil, 0o600)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "40-dir.conf"), 0o700); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	paths, err := configFragments(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "10-listen.conf"),
+		filepath.Join(dir, "20-http.toml"),
+		filepath.Join(dir, "30-net.yml"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("expected fragments %v, got %v", want, paths)
+	}
+}
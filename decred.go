@@ -6,12 +6,15 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"net/netip"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -19,54 +22,89 @@ import (
 	"github.com/decred/dcrd/wire"
 )
 
-const (
-	// defaultAddressTimeout defines the duration to wait for new addresses.
-	defaultAddressTimeout = time.Minute * 10
-
-	// defaultNodeTimeout defines the timeout on responses from a node.
-	defaultNodeTimeout = time.Second * 3
-)
-
 type crawler struct {
 	params *chaincfg.Params
+	cfg    *crawlConfig
 	amgr   *Manager
-	log    *log.Logger
+	log    *slog.Logger
 }
 
-func newCrawler(params *chaincfg.Params, amgr *Manager, log *log.Logger) *crawler {
+func newCrawler(params *chaincfg.Params, cfg *crawlConfig, amgr *Manager, log *slog.Logger) *crawler {
 	return &crawler{
 		params: params,
+		cfg:    cfg,
 		amgr:   amgr,
 		log:    log,
 	}
 }
 
+// addrsFromMsg returns the valid addresses contained in an addr message.
+func addrsFromMsg(msg *wire.MsgAddr) []netip.AddrPort {
+	addrs := make([]netip.AddrPort, 0, len(msg.AddrList))
+	for _, entry := range msg.AddrList {
+		if addr, ok := netip.AddrFromSlice(entry.IP); ok {
+			addrs = append(addrs, netip.AddrPortFrom(addr, entry.Port))
+		}
+	}
+	return addrs
+}
+
+// splitSelfAdvertised separates the address a peer dialed at dialed claims for
+// itself out of addrs when it differs from dialed. Nodes advertise their own
+// address in an addr message containing a single entry which is sent without
+// being requested. Other addresses, including those sharing the IP of the peer,
+// may belong to other nodes on the same host and are never treated as self.
+func splitSelfAdvertised(dialed netip.AddrPort, addrs []netip.AddrPort,
+	unsolicited bool) (others []netip.AddrPort, self netip.AddrPort) {
+
+	if !unsolicited || len(addrs) != 1 {
+		return addrs, netip.AddrPort{}
+	}
+	addrPort := netip.AddrPortFrom(addrs[0].Addr().Unmap(), addrs[0].Port())
+	if addrPort == dialed {
+		return addrs, netip.AddrPort{}
+	}
+	return nil, addrPort
+}
+
 func (c *crawler) testPeer(ctx context.Context, ip netip.AddrPort) {
 	onaddr := make(chan struct{}, 1)
 	verack := make(chan struct{}, 1)
+
+	// Addr messages received before the getaddr request is sent are
+	// unsolicited. The address the peer advertises for itself is only
+	// recorded once the probe is done, so that it is not cleared by marking
+	// the peer good.
+	var getAddrSent atomic.Bool
+	var selfAdvertised atomic.Pointer[netip.AddrPort]
 	config := peer.Config{
-		UserAgentName:    appName,
-		UserAgentVersion: "0.0.1",
+		UserAgentName:    c.cfg.userAgentName,
+		UserAgentVersion: c.cfg.userAgentVersion,
 		Net:              c.params.Net,
 		DisableRelayTx:   true,
 
 		Listeners: peer.MessageListeners{
 			OnAddr: func(p *peer.Peer, msg *wire.MsgAddr) {
-				n := make([]netip.AddrPort, 0, len(msg.AddrList))
-				for _, entry := range msg.AddrList {
-					if addr, ok := netip.AddrFromSlice(entry.IP); ok {
-						addrPort := netip.AddrPortFrom(addr, entry.Port)
-						n = append(n, addrPort)
-					}
+				unsolicited := !getAddrSent.Load()
+				addrs, self := splitSelfAdvertised(ip, addrsFromMsg(msg),
+					unsolicited)
+				if self.IsValid() {
+					c.log.Debug("Peer advertises itself at another address",
+						"peer", p.Addr(), "advertised", self)
+					selfAdvertised.Store(&self)
+				}
+				added := c.amgr.AddAddresses(addrs)
+				if unsolicited {
+					// Keep waiting for the response to getaddr.
+					return
 				}
-				added := c.amgr.AddAddresses(n)
-				c.log.Printf("Peer %v sent %v addresses, %d new",
-					p.Addr(), len(msg.AddrList), added)
+				c.log.Debug("Received addresses", "peer", p.Addr(),
+					"addrs", len(msg.AddrList), "new", added)
 				onaddr <- struct{}{}
 			},
 			OnVerAck: func(p *peer.Peer, _ *wire.MsgVerAck) {
-				c.log.Printf("Adding peer %v with services %v pver %d",
-					p.NA().IP.String(), p.Services(), p.ProtocolVersion())
+				c.log.Debug("Adding peer", "peer", p.NA().IP.String(),
+					"services", p.Services(), "pver", p.ProtocolVersion())
 				verack <- struct{}{}
 			},
 		},
@@ -75,17 +113,23 @@ func (c *crawler) testPeer(ctx context.Context, ip netip.AddrPort) {
 	host := ip.String()
 	p, err := peer.NewOutboundPeer(&config, host)
 	if err != nil {
-		c.log.Printf("NewOutboundPeer on %v: %v", host, err)
+		c.log.Warn("NewOutboundPeer failed", "peer", host, "err", err)
 		return
 	}
 
 	// Time stamp the attempt after disconnect or dial error so we don't prune
 	// this peer before or during its test.
 	defer c.amgr.Attempt(ip)
+	defer func() {
+		if self := selfAdvertised.Load(); self != nil {
+			c.amgr.AdvertisedMismatch(ip, *self)
+		}
+	}()
 
-	ctxTimeout, cancel := context.WithTimeout(ctx, defaultNodeTimeout)
+	ctxTimeout, cancel := context.WithTimeout(ctx, c.cfg.NodeTimeout)
 	defer cancel()
 	var dialer net.Dialer
+	start := time.Now()
 	conn, err := dialer.DialContext(ctxTimeout, "tcp", p.Addr())
 	if err != nil {
 		return
@@ -100,13 +144,15 @@ func (c *crawler) testPeer(ctx context.Context, ip netip.AddrPort) {
 			return
 		}
 		// Mark this peer as a good node.
-		c.amgr.Good(ip, p.Services(), p.ProtocolVersion())
+		c.amgr.Good(ip, p.Services(), p.ProtocolVersion(), p.UserAgent(),
+			p.LastBlock(), time.Since(start))
 
 		// Ask peer for some addresses.
+		getAddrSent.Store(true)
 		p.QueueMessage(wire.NewMsgGetAddr(), nil)
 
-	case <-time.After(defaultNodeTimeout):
-		c.log.Printf("verack timeout on peer %v", p.Addr())
+	case <-time.After(c.cfg.NodeTimeout):
+		c.log.Debug("verack timeout", "peer", p.Addr())
 		return
 	case <-ctx.Done():
 		return
@@ -114,8 +160,8 @@ func (c *crawler) testPeer(ctx context.Context, ip netip.AddrPort) {
 
 	select {
 	case <-onaddr:
-	case <-time.After(defaultNodeTimeout):
-		c.log.Printf("getaddr timeout on peer %v", p.Addr())
+	case <-time.After(c.cfg.NodeTimeout):
+		c.log.Debug("getaddr timeout", "peer", p.Addr())
 	case <-ctx.Done():
 	}
 }
@@ -126,26 +172,41 @@ func (c *crawler) run(ctx context.Context) {
 			return
 		}
 
-		ips := c.amgr.Addresses()
+		ips := c.amgr.Addresses(c.cfg.MaxProbes)
 		if len(ips) == 0 {
-			c.log.Printf("No stale addresses -- sleeping for %v", defaultAddressTimeout)
+			c.log.Info("No stale addresses -- sleeping", "duration", c.cfg.IdleTimeout)
 			select {
-			case <-time.After(defaultAddressTimeout):
+			case <-time.After(c.cfg.IdleTimeout):
 			case <-ctx.Done():
 				return
 			}
 			continue
 		}
 
-		var wg sync.WaitGroup
-		wg.Add(len(ips))
+		// Probe addresses within the same subnet serially so a single
+		// operator's hosting range isn't hit with a burst of connections.
+		subnets := make(map[netip.Prefix][]netip.AddrPort)
 		for _, ip := range ips {
-			go func(ip netip.AddrPort) {
+			subnet := probeSubnet(ip.Addr())
+			subnets[subnet] = append(subnets[subnet], ip)
+		}
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		wg.Add(len(subnets))
+		for _, ips := range subnets {
+			go func(ips []netip.AddrPort) {
 				defer wg.Done()
-				c.testPeer(ctx, ip)
-			}(ip)
+				for _, ip := range ips {
+					if ctx.Err() != nil {
+						return
+					}
+					c.testPeer(ctx, ip)
+				}
+			}(ips)
 		}
 		wg.Wait()
+		c.amgr.CrawlCycle(start, time.Since(start), len(ips))
 	}
 }
 
@@ -164,59 +225,177 @@ func run() int {
 		return 1
 	}
 
-	defer log.Print("Bye!")
+	if cfg.Validate {
+		if !validateConfig(cfg, os.Stdout) {
+			return 1
+		}
+		return 0
+	}
+
+	// Fail before starting any services rather than leaving some of them
+	// running.
+	if err := checkStartup(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 1
+	}
+
+	logLevels := cfg.logLevels
+	log := logLevels.logger(subsysMain)
+	slog.SetDefault(log)
+	defer log.Info("Bye!")
+
+	// Log files of the networks are closed once all subsystems are done.
+	var logFiles []*os.File
+	defer func() {
+		for _, f := range logFiles {
+			f.Close()
+		}
+	}()
 
 	// Wait for all subsystems to shut down before returning and allowing the
 	// process to end.
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
+	// Networks are additionally served by the shared listener when enabled.
+	var sharedMux *http.ServeMux
+	if cfg.HTTPListen != "" {
+		sharedMux = newSharedServeMux()
+	}
+
+	// The address managers of all networks are checked by the systemd
+	// watchdog and updated with the Tor exit list.
+	var amgrs []*Manager
+
 	runNet := func(cfg *netConfig) error {
 		// Nothing to do if this network is not enabled.
 		if !cfg.Enabled {
 			return nil
 		}
 
-		// Prefix log lines with current network, e.g. "[mainnet]" or "[testnet]".
-		logPrefix := fmt.Sprintf("[%.7s] ", cfg.netParams.Name)
-		log := log.New(os.Stdout, logPrefix, log.LstdFlags|log.Lmsgprefix)
+		// Log records of the network may be written to their own file.
+		netLevels := logLevels
+		if cfg.LogFile != "" {
+			levels, f, err := logLevels.withFile(cfg.LogFile)
+			if err != nil {
+				log.Error("Failed to open log file", "net", cfg.name,
+					"err", err)
+				return err
+			}
+			logFiles = append(logFiles, f)
+			netLevels = levels
+		}
+
+		// Tag log records with the current network, e.g. "net=mainnet".
+		netLogger := func(subsystem string) *slog.Logger {
+			return netLevels.logger(subsystem).With("net", cfg.name)
+		}
+		log := netLogger(subsysMain)
 
-		amgr, err := NewManager(cfg.dataDir, log)
+		amgr, err := NewManager(cfg.dataDir, cfg.Crawl.StaleTimeout, cfg.Crawl.StableAge,
+			netLogger(subsysManager))
 		if err != nil {
-			log.Println(err)
+			log.Error("Failed to create address manager", "err", err)
 			return err
 		}
 
+		amgr.setPolicy(cfg.policy)
+
 		amgr.AddAddresses([]netip.AddrPort{cfg.seederIP})
+		amgrs = append(amgrs, amgr)
 
-		c := newCrawler(cfg.netParams, amgr, log)
+		c := newCrawler(cfg.netParams, &cfg.Crawl, amgr, netLogger(subsysCrawler))
 
-		server, err := newServer(cfg.Listen, amgr, log)
-		if err != nil {
-			log.Println(err)
-			return err
+		// No servers are created when only crawling.
+		httpLog := netLogger(subsysHTTP)
+		var mux *http.ServeMux
+		if len(cfg.Listen) > 0 || sharedMux != nil {
+			mux = newServeMux(cfg, amgr, httpLog)
+		}
+		// The TLS configuration is shared by the HTTP and gRPC servers so
+		// certificates are only loaded or obtained once.
+		var tlsConfig *tls.Config
+		if len(cfg.Listen) > 0 || cfg.GRPCListen != "" {
+			tlsConfig, err = serverTLSConfig(&cfg.HTTP)
+			if err != nil {
+				log.Error("Failed to create TLS configuration", "err", err)
+				return err
+			}
+		}
+		var server *server
+		if len(cfg.Listen) > 0 {
+			server, err = newServer(cfg, tlsConfig, mux, httpLog)
+			if err != nil {
+				log.Error("Failed to create HTTP server", "err", err)
+				return err
+			}
+		}
+
+		if sharedMux != nil {
+			registerSharedHandlers(sharedMux, cfg.name, mux)
+		}
+
+		var grpcServer *grpcServer
+		if cfg.GRPCListen != "" {
+			grpcServer, err = newGRPCServer(cfg, tlsConfig, amgr, netLogger(subsysGRPC))
+			if err != nil {
+				log.Error("Failed to create gRPC server", "err", err)
+				return err
+			}
+		}
+
+		// Optionally accept inbound peers to passively collect the addresses
+		// they gossip.
+		var inbound *inboundListener
+		if cfg.P2PListen != "" {
+			inbound, err = newInboundListener(cfg.P2PListen, cfg.netParams,
+				&cfg.Crawl, amgr, netLogger(subsysP2P))
+			if err != nil {
+				log.Error("Failed to create inbound listener", "err", err)
+				return err
+			}
 		}
 
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			amgr.run(ctx) // Only returns on context cancellation.
-			log.Print("Address manager done.")
+			log.Info("Address manager done.")
 		}()
 
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			c.run(ctx) // Only returns on context cancellation.
-			log.Print("Crawler done.")
+			log.Info("Crawler done.")
 		}()
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			server.run(ctx) // Only returns on context cancellation.
-			log.Print("HTTP server done.")
-		}()
+		if inbound != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				inbound.run(ctx) // Only returns on context cancellation.
+				log.Info("Inbound listener done.")
+			}()
+		}
+
+		if server != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				server.run(ctx) // Only returns on context cancellation.
+				log.Info("HTTP server done.")
+			}()
+		}
+
+		if grpcServer != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				grpcServer.run(ctx) // Only returns on context cancellation.
+				log.Info("gRPC server done.")
+			}()
+		}
 
 		return nil
 	}
@@ -233,5 +412,69 @@ func run() int {
 		return 1
 	}
 
+	err = runNet(cfg.Simnet)
+	if err != nil {
+		cancel()
+		return 1
+	}
+
+	if sharedMux != nil {
+		// The shared listener is tuned by the HTTP options of the first
+		// enabled network.
+		var tuning *httpConfig
+		for _, netCfg := range []*netConfig{cfg.Mainnet, cfg.Testnet, cfg.Simnet} {
+			if netCfg.Enabled {
+				tuning = &netCfg.HTTP
+				break
+			}
+		}
+		server, err := newHTTPServer([]string{cfg.HTTPListen}, nil, sharedMux, tuning,
+			logLevels.logger(subsysHTTP))
+		if err != nil {
+			log.Error("Failed to create shared HTTP server", "err", err)
+			cancel()
+			return 1
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.run(ctx) // Only returns on context cancellation.
+			log.Info("Shared HTTP server done.")
+		}()
+	}
+
+	// All listeners are bound and the known nodes loaded at this point.
+	closeUnusedActivated(log)
+	if err := sdNotify("READY=1"); err != nil {
+		log.Warn("Failed to notify systemd of readiness", "err", err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		if err := sdNotify("STOPPING=1"); err != nil {
+			log.Warn("Failed to notify systemd of shutdown", "err", err)
+		}
+	}()
+
+	if cfg.TorExits {
+		log.Info("Excluding Tor exits from good nodes", "url", cfg.TorExitList,
+			"refresh", cfg.TorExitRefresh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runTorExits(ctx, cfg.TorExitList, cfg.TorExitRefresh, amgrs, log)
+		}()
+	}
+
+	if interval := watchdogInterval(); interval > 0 {
+		log.Info("Pinging systemd watchdog", "interval", interval/2)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runWatchdog(ctx, interval, amgrs, log)
+		}()
+	}
+
 	return 0
 }
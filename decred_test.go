@@ -0,0 +1,115 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+func Test_AddrsFromMsg(t *testing.T) {
+	newMsg := func(ips ...net.IP) *wire.MsgAddr {
+		msg := wire.NewMsgAddr()
+		for _, ip := range ips {
+			msg.AddAddress(wire.NewNetAddressIPPort(ip, 9108, wire.SFNodeNetwork))
+		}
+		return msg
+	}
+
+	msgTests := map[string]struct {
+		msg      *wire.MsgAddr
+		expected []netip.AddrPort
+	}{
+		"empty": {
+			newMsg(),
+			[]netip.AddrPort{},
+		},
+		"ip4 and ip6": {
+			newMsg(net.ParseIP("8.8.8.8").To4(), net.ParseIP("2001:4860::1")),
+			[]netip.AddrPort{
+				netip.MustParseAddrPort("8.8.8.8:9108"),
+				netip.MustParseAddrPort("[2001:4860::1]:9108"),
+			},
+		},
+		"invalid ip": {
+			newMsg(net.IP{1, 2, 3}, net.ParseIP("8.8.8.8").To4()),
+			[]netip.AddrPort{netip.MustParseAddrPort("8.8.8.8:9108")},
+		},
+	}
+
+	for testName, test := range msgTests {
+		actual := addrsFromMsg(test.msg)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+}
+
+func Test_SplitSelfAdvertised(t *testing.T) {
+	dialed := netip.MustParseAddrPort("8.8.8.8:9108")
+	other := netip.MustParseAddrPort("8.8.4.4:9108")
+	sameHost := netip.MustParseAddrPort("8.8.8.8:19108")
+
+	splitTests := map[string]struct {
+		addrs          []netip.AddrPort
+		unsolicited    bool
+		expectedOthers []netip.AddrPort
+		expectedSelf   netip.AddrPort
+	}{
+		"unsolicited self at another address": {
+			[]netip.AddrPort{sameHost},
+			true,
+			nil,
+			sameHost,
+		},
+		"unsolicited self at dialed address": {
+			[]netip.AddrPort{dialed},
+			true,
+			[]netip.AddrPort{dialed},
+			netip.AddrPort{},
+		},
+		"unsolicited ip4-mapped dialed address": {
+			[]netip.AddrPort{netip.MustParseAddrPort("[::ffff:8.8.8.8]:9108")},
+			true,
+			[]netip.AddrPort{netip.MustParseAddrPort("[::ffff:8.8.8.8]:9108")},
+			netip.AddrPort{},
+		},
+		"unsolicited several addresses": {
+			[]netip.AddrPort{sameHost, other},
+			true,
+			[]netip.AddrPort{sameHost, other},
+			netip.AddrPort{},
+		},
+		"solicited single address": {
+			[]netip.AddrPort{other},
+			false,
+			[]netip.AddrPort{other},
+			netip.AddrPort{},
+		},
+		"solicited node on the same host": {
+			[]netip.AddrPort{sameHost, other},
+			false,
+			[]netip.AddrPort{sameHost, other},
+			netip.AddrPort{},
+		},
+	}
+
+	for testName, test := range splitTests {
+		others, self := splitSelfAdvertised(dialed, test.addrs, test.unsolicited)
+		if !reflect.DeepEqual(others, test.expectedOthers) {
+			t.Fatalf("%s: expected others %v, got %v", testName,
+				test.expectedOthers, others)
+		}
+		if self != test.expectedSelf {
+			t.Fatalf("%s: expected self %v, got %v", testName,
+				test.expectedSelf, self)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/decred/dcrseeder/api"
+)
+
+// fieldProjection encodes node records of type T as JSON objects holding only
+// a subset of their fields.
+type fieldProjection[T any] struct {
+	// indices are the indices of the selected struct fields in declaration
+	// order and keys their quoted JSON names.
+	indices []int
+	keys    [][]byte
+}
+
+// newFieldProjection returns the projection of T onto the fields named by the
+// fields query parameter of r, or nil when all fields are requested. Unknown
+// names are ignored, and the fields are always encoded in declaration order.
+func newFieldProjection[T any](r *http.Request) *fieldProjection[T] {
+	fields := r.URL.Query().Get(api.Fields)
+	if fields == "" {
+		return nil
+	}
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(fields, ",") {
+		selected[strings.TrimSpace(name)] = true
+	}
+
+	var p fieldProjection[T]
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if !selected[name] {
+			continue
+		}
+		key, _ := json.Marshal(name)
+		p.indices = append(p.indices, i)
+		p.keys = append(p.keys, key)
+	}
+	return &p
+}
+
+// appendJSON appends the JSON encoding of the selected fields of node to buf.
+func (p *fieldProjection[T]) appendJSON(buf []byte, node *T) ([]byte, error) {
+	v := reflect.ValueOf(node).Elem()
+	buf = append(buf, '{')
+	for i, index := range p.indices {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		value, err := json.Marshal(v.Field(index).Interface())
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, p.keys[i]...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// encode writes the selected fields of node as a single line of JSON.
+func (p *fieldProjection[T]) encode(w io.Writer, node *T) error {
+	buf, err := p.appendJSON(nil, node)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(buf, '\n'))
+	return err
+}
+
+// encodeArray writes the selected fields of nodes as a JSON array.
+func (p *fieldProjection[T]) encodeArray(w io.Writer, nodes []T) error {
+	buf := []byte{'['}
+	for i := range nodes {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		buf, err = p.appendJSON(buf, &nodes[i])
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(append(buf, "]\n"...))
+	return err
+}
+
+// project returns the selected columns of a CSV row of a node. The columns
+// must be in the declaration order of the fields of T.
+func (p *fieldProjection[T]) project(row []string) []string {
+	projected := make([]string, 0, len(p.indices))
+	for _, index := range p.indices {
+		projected = append(projected, row[index])
+	}
+	return projected
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrseeder/api"
+)
+
+func Test_FieldProjection(t *testing.T) {
+	node := api.NodeV2{
+		Host:            "8.8.8.8:9108",
+		Services:        1,
+		ProtocolVersion: 11,
+		LastSeen:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		UserAgent:       "/dcrwire:1.0.0/",
+	}
+	projectionTests := map[string]struct {
+		target       string
+		expectedJSON string
+		expectedCSV  []string
+	}{
+		"host": {
+			"/api/v2/addrs?fields=host",
+			`{"host":"8.8.8.8:9108"}`,
+			[]string{"host"},
+		},
+		"declaration order": {
+			"/api/v2/addrs?fields=useragent,lastseen,host",
+			`{"host":"8.8.8.8:9108","lastseen":"2026-01-02T03:04:05Z","useragent":"/dcrwire:1.0.0/"}`,
+			[]string{"host", "lastseen", "useragent"},
+		},
+		"unknown": {
+			"/api/v2/addrs?fields=pver,bogus",
+			`{"pver":11}`,
+			[]string{"pver"},
+		},
+	}
+
+	for testName, test := range projectionTests {
+		r := httptest.NewRequest("GET", test.target, nil)
+		p := newFieldProjection[api.NodeV2](r)
+		var buf bytes.Buffer
+		err := p.encode(&buf, &node)
+		if err != nil {
+			t.Fatalf("%s: %v", testName, err)
+		}
+		if buf.String() != test.expectedJSON+"\n" {
+			t.Fatalf("%s: expected JSON %s, got %s", testName,
+				test.expectedJSON, buf.String())
+		}
+		csvHeader := p.project(nodeV2CSVHeader)
+		if !reflect.DeepEqual(csvHeader, test.expectedCSV) {
+			t.Fatalf("%s: expected CSV header %v, got %v", testName,
+				test.expectedCSV, csvHeader)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/api/v2/addrs", nil)
+	if p := newFieldProjection[api.NodeV2](r); p != nil {
+		t.Fatalf("expected no projection, got %+v", p)
+	}
+}
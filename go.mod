@@ -1,13 +1,19 @@
 module github.com/decred/dcrseeder
 
-go 1.18
+go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/decred/dcrd/chaincfg/v3 v3.2.1
 	github.com/decred/dcrd/dcrutil/v4 v4.0.2
 	github.com/decred/dcrd/peer/v3 v3.1.0
 	github.com/decred/dcrd/wire v1.7.0
 	github.com/jessevdk/go-flags v1.5.0
+	golang.org/x/crypto v0.25.0
+	golang.org/x/sys v0.22.0
+	google.golang.org/grpc v1.64.1
+	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,6 +32,8 @@ require (
 	github.com/decred/go-socks v1.1.0 // indirect
 	github.com/decred/slog v1.2.0 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.5 // indirect
-	golang.org/x/sys v0.9.0 // indirect
+	golang.org/x/net v0.26.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 )
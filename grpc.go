@@ -0,0 +1,153 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrseeder/api"
+	"github.com/decred/dcrseeder/api/seederrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// grpcServer implements the gRPC seed service of a single network.
+type grpcServer struct {
+	seederrpc.UnimplementedSeederServer
+
+	cfg      *httpConfig
+	amgr     *Manager
+	srv      *grpc.Server
+	listener net.Listener
+	quit     chan struct{}
+	log      *slog.Logger
+}
+
+// newGRPCServer returns the gRPC server of the network described by cfg. The
+// service is served over TLS with the configuration of the HTTP API when
+// tlsConfig is not nil.
+func newGRPCServer(cfg *netConfig, tlsConfig *tls.Config, amgr *Manager,
+	log *slog.Logger) (*grpcServer, error) {
+
+	listener, err := listenTCP(cfg.GRPCListen)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := &grpcServer{
+		cfg:      &cfg.HTTP,
+		amgr:     amgr,
+		srv:      grpc.NewServer(opts...),
+		listener: listener,
+		quit:     make(chan struct{}),
+		log:      log,
+	}
+	seederrpc.RegisterSeederServer(s.srv, s)
+	return s, nil
+}
+
+// rpcAddrFilter returns the filter on good nodes requested by f. Invalid
+// fields are ignored. The number of nodes is capped by maxAddrs.
+func rpcAddrFilter(f *seederrpc.AddrFilter, maxAddrs int) *AddrFilter {
+	filter := AddrFilter{
+		ProtocolVersion: f.GetProtocolVersion(),
+		Services:        wire.ServiceFlag(f.GetServices()),
+		Limit:           defaultMaxAddresses,
+	}
+	if ipVersion := f.GetIpVersion(); ipVersion == 4 || ipVersion == 6 {
+		filter.IPVersion = ipVersion
+	}
+	if limit := f.GetLimit(); limit > 0 {
+		filter.Limit = int(min(uint64(limit), uint64(maxAddrs)))
+	}
+	if filter.Limit > maxAddrs {
+		filter.Limit = maxAddrs
+	}
+	return &filter
+}
+
+// rpcNodes converts nodes to their gRPC representation.
+func rpcNodes(nodes []api.Node) *seederrpc.GetAddrsResponse {
+	resp := &seederrpc.GetAddrsResponse{
+		Nodes: make([]*seederrpc.Node, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		resp.Nodes = append(resp.Nodes, &seederrpc.Node{
+			Host:            node.Host,
+			Services:        node.Services,
+			ProtocolVersion: node.ProtocolVersion,
+		})
+	}
+	return resp
+}
+
+// GetAddrs returns a selection of reliable nodes matching the filter.
+func (s *grpcServer) GetAddrs(_ context.Context, req *seederrpc.GetAddrsRequest) (*seederrpc.GetAddrsResponse, error) {
+	filter := rpcAddrFilter(req.GetFilter(), s.cfg.MaxAddrs)
+	return rpcNodes(s.amgr.GoodAddresses(filter)), nil
+}
+
+// WatchAddrs streams a selection of reliable nodes matching the filter
+// immediately and whenever the set of reliable nodes changes.
+func (s *grpcServer) WatchAddrs(req *seederrpc.GetAddrsRequest, stream seederrpc.Seeder_WatchAddrsServer) error {
+	filter := rpcAddrFilter(req.GetFilter(), s.cfg.MaxAddrs)
+	for {
+		// Obtain the change notification before the nodes so no change is
+		// missed.
+		changed := s.amgr.GoodChanged()
+		err := stream.Send(rpcNodes(s.amgr.GoodAddresses(filter)))
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-s.quit:
+			return nil
+		}
+	}
+}
+
+func (s *grpcServer) run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	// Add the graceful shutdown to the waitgroup.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Wait until context is canceled before shutting down the server.
+		// Streams are ended first since they would otherwise prevent the
+		// graceful stop from completing.
+		<-ctx.Done()
+		close(s.quit)
+		s.srv.GracefulStop()
+	}()
+
+	s.log.Info("gRPC listening", "addr", s.listener.Addr())
+	err := s.srv.Serve(s.listener)
+	switch {
+	case errors.Is(err, grpc.ErrServerStopped):
+		// The server was stopped before it started serving, in which case
+		// the listener may still be open.
+		s.listener.Close()
+	case err != nil:
+		s.log.Error("unexpected (grpc.Server).Serve error", "err", err)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrseeder/api/seederrpc"
+	"google.golang.org/grpc"
+)
+
+func Test_RPCAddrFilter(t *testing.T) {
+	const maxAddrs = 100
+	filterTests := map[string]struct {
+		filter         *seederrpc.AddrFilter
+		expectedFilter AddrFilter
+	}{
+		"no filter": {
+			nil,
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"default": {
+			&seederrpc.AddrFilter{},
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"filters": {
+			&seederrpc.AddrFilter{IpVersion: 6, ProtocolVersion: 9, Services: 1},
+			AddrFilter{IPVersion: 6, ProtocolVersion: 9, Services: 1,
+				Limit: defaultMaxAddresses},
+		},
+		"invalid ip version": {
+			&seederrpc.AddrFilter{IpVersion: 5},
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"limit": {
+			&seederrpc.AddrFilter{Limit: 50},
+			AddrFilter{Limit: 50},
+		},
+		"limit capped": {
+			&seederrpc.AddrFilter{Limit: 5000},
+			AddrFilter{Limit: maxAddrs},
+		},
+	}
+
+	for testName, test := range filterTests {
+		actualFilter := rpcAddrFilter(test.filter, maxAddrs)
+		if *actualFilter != test.expectedFilter {
+			t.Fatalf("%s: expected filter %+v, got %+v",
+				testName, test.expectedFilter, *actualFilter)
+		}
+	}
+
+	// The default limit is capped when fewer nodes may be returned.
+	actualFilter := rpcAddrFilter(&seederrpc.AddrFilter{}, 10)
+	if actualFilter.Limit != 10 {
+		t.Fatalf("expected default limit capped at 10, got %d",
+			actualFilter.Limit)
+	}
+}
+
+// watchStream is a Seeder_WatchAddrsServer passing the sent responses to a
+// channel.
+type watchStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	resps chan *seederrpc.GetAddrsResponse
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(resp *seederrpc.GetAddrsResponse) error {
+	s.resps <- resp
+	return nil
+}
+
+func Test_WatchAddrs(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, s := range []string{"8.8.8.8:9108", "8.8.4.4:9108"} {
+		addrPort := netip.MustParseAddrPort(s)
+		amgr.AddAddresses([]netip.AddrPort{addrPort})
+		node := amgr.nodes[addrPort.String()]
+		node.FirstSuccess = now.Add(-2 * time.Hour)
+		node.LastSuccess = now
+	}
+	s := &grpcServer{
+		cfg:  &httpConfig{MaxAddrs: 100},
+		amgr: amgr,
+		quit: make(chan struct{}),
+		log:  log,
+	}
+
+	// watch starts watching the good nodes until ctx is done or the server
+	// quits and returns the stream and the result of the watch.
+	watch := func(ctx context.Context) (*watchStream, chan error) {
+		stream := &watchStream{
+			ctx:   ctx,
+			resps: make(chan *seederrpc.GetAddrsResponse),
+		}
+		errc := make(chan error, 1)
+		go func() {
+			errc <- s.WatchAddrs(&seederrpc.GetAddrsRequest{}, stream)
+		}()
+		return stream, errc
+	}
+	receive := func(stream *watchStream, expectedNodes int) {
+		t.Helper()
+		select {
+		case resp := <-stream.resps:
+			if len(resp.Nodes) != expectedNodes {
+				t.Fatalf("expected %d nodes, got %d", expectedNodes,
+					len(resp.Nodes))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for nodes")
+		}
+	}
+	result := func(errc chan error) error {
+		t.Helper()
+		select {
+		case err := <-errc:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the watch to end")
+			return nil
+		}
+	}
+
+	// The good nodes are sent immediately and again once they change.
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, errc := watch(ctx)
+	receive(stream, 2)
+	if _, err := amgr.Ban(netip.MustParseAddr("8.8.4.4")); err != nil {
+		t.Fatal(err)
+	}
+	receive(stream, 1)
+
+	// The watch ends with the error of the stream context once the client
+	// is gone.
+	cancel()
+	if err := result(errc); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+
+	// The watch ends without an error when the server quits.
+	stream, errc = watch(context.Background())
+	receive(stream, 1)
+	close(s.quit)
+	if err := result(errc); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func Test_GRPCServerStoppedEarly(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newGRPCServer(&netConfig{GRPCListen: "127.0.0.1:0"}, nil,
+		amgr, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shutting down before serving is not an error and frees the listener.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.run(ctx)
+	if strings.Contains(buf.String(), "level=ERROR") {
+		t.Fatalf("expected no errors, got %q", buf.String())
+	}
+	if _, err := s.listener.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected listener to be closed, got %v", err)
+	}
+}
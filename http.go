@@ -5,26 +5,82 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
 	"net"
 	"net/http"
+	"net/netip"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/dcrseeder/api"
+	"golang.org/x/crypto/acme/autocert"
+	"google.golang.org/protobuf/proto"
 )
 
-const defaultHTTPTimeout = 10 * time.Second
+// formatMediaTypes maps the response formats of /api/addrs to the media types
+// which select them in the Accept header. The text format is only selected by
+// the format query parameter, since NDJSON has always been served as
+// text/plain and clients accepting it expect NDJSON.
+var formatMediaTypes = map[string]string{
+	api.FormatNDJSON:   "application/x-ndjson",
+	api.FormatJSON:     "application/json",
+	api.FormatCSV:      "text/csv",
+	api.FormatProtobuf: "application/x-protobuf",
+}
 
-func httpGetAddrs(w http.ResponseWriter, r *http.Request, amgr *Manager, log *log.Logger) {
-	var wantedIP uint32
-	var wantedPV uint32
-	var wantedSF wire.ServiceFlag
+// addrsFormat returns the response format requested by r. The format query
+// parameter takes precedence over the first recognized media type of the
+// Accept header, and the default is NDJSON. Protobuf media types in the Accept
+// header are skipped in favor of the next acceptable type unless the endpoint
+// serves protobuf.
+func addrsFormat(r *http.Request, protobuf bool) string {
+	if format := r.URL.Query().Get(api.Format); format != "" {
+		if _, ok := formatMediaTypes[format]; ok || format == api.FormatText {
+			return format
+		}
+		return api.FormatNDJSON
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(accept)
+		if err != nil {
+			continue
+		}
+		for format, formatType := range formatMediaTypes {
+			if format == api.FormatProtobuf && !protobuf {
+				continue
+			}
+			if mediaType == formatType {
+				return format
+			}
+		}
+	}
+
+	return api.FormatNDJSON
+}
+
+// addrFilter returns the filter on good nodes requested by the query
+// parameters of r. Invalid parameters are ignored. The number of nodes is
+// capped by maxAddrs.
+func addrFilter(r *http.Request, maxAddrs int) *AddrFilter {
+	filter := AddrFilter{
+		Limit: defaultMaxAddresses,
+	}
 
 	query := r.URL.Query()
 
@@ -32,23 +88,321 @@ func httpGetAddrs(w http.ResponseWriter, r *http.Request, amgr *Manager, log *lo
 	if requestedIP != "" {
 		u, _ := strconv.ParseUint(requestedIP, 10, 32)
 		if u == 4 || u == 6 {
-			wantedIP = uint32(u)
+			filter.IPVersion = uint32(u)
 		}
 	}
 
 	requestedPV := query.Get(api.ProtocolVersion)
 	if requestedPV != "" {
 		u, _ := strconv.ParseUint(requestedPV, 10, 32)
-		wantedPV = uint32(u)
+		filter.ProtocolVersion = uint32(u)
 	}
 
 	requestedSF := query.Get(api.ServiceFlag)
 	if requestedSF != "" {
 		u, _ := strconv.ParseUint(requestedSF, 10, 64)
-		wantedSF = wire.ServiceFlag(u)
+		filter.Services = wire.ServiceFlag(u)
+	}
+
+	requestedMaxAge := query.Get(api.MaxAge)
+	if requestedMaxAge != "" {
+		u, err := strconv.ParseUint(requestedMaxAge, 10, 31)
+		if err == nil && u > 0 {
+			filter.MaxAge = time.Duration(u) * time.Minute
+		}
+	}
+
+	switch requestedSort := query.Get(api.Sort); requestedSort {
+	case api.SortUptime, api.SortLastSuccess, api.SortLatency:
+		filter.Sort = requestedSort
+	}
+
+	requestedLimit := query.Get(api.Limit)
+	if requestedLimit != "" {
+		u, err := strconv.ParseUint(requestedLimit, 10, 31)
+		if err == nil && u > 0 {
+			filter.Limit = int(u)
+		}
+	}
+	if filter.Limit > maxAddrs {
+		filter.Limit = maxAddrs
+	}
+
+	requestedOffset := query.Get(api.Offset)
+	if requestedOffset != "" {
+		u, err := strconv.ParseUint(requestedOffset, 10, 31)
+		if err == nil {
+			filter.Paged = true
+			filter.Offset = int(u)
+		}
+	}
+
+	return &filter
+}
+
+// addrsMaxAge is how long clients may cache responses of GetAddrsPath. The
+// responses are private so shared caches do not serve the same random
+// selection of nodes to all of their clients.
+const addrsMaxAge = time.Minute
+
+// addrsETag returns a weak entity tag for the response to r given the digest
+// of the set of good nodes. The tag is weak since the random selection of
+// nodes differs between otherwise equivalent responses.
+func addrsETag(r *http.Request, goodHash [sha256.Size]byte) string {
+	h := sha256.New()
+	h.Write(goodHash[:])
+	h.Write([]byte(addrsFormat(r, true)))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Query().Encode()))
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// maxAddrsWait is the maximum time a request to GetAddrsPath waits for the set
+// of good nodes to change.
+const maxAddrsWait = 5 * time.Minute
+
+// notModified reports whether the response to r is unchanged since the client
+// fetched it according to its conditional request headers. If-Modified-Since
+// is only evaluated without If-None-Match.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatch(inm, etag)
 	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(t)
+}
 
-	nodes := amgr.GoodAddresses(wantedIP, wantedPV, wantedSF)
+// addrsWait returns the time the client requested to wait for a change of the
+// set of good nodes with the Wait query parameter, capped at maxAddrsWait.
+func addrsWait(r *http.Request) time.Duration {
+	u, err := strconv.ParseUint(r.URL.Query().Get(api.Wait), 10, 31)
+	if err != nil {
+		return 0
+	}
+	wait := time.Duration(u) * time.Second
+	if wait > maxAddrsWait {
+		wait = maxAddrsWait
+	}
+	return wait
+}
+
+// etagMatch reports whether the If-None-Match header value matches etag using
+// the weak comparison.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// setAddrsCacheHeaders sets the caching headers of responses to GetAddrsPath.
+func setAddrsCacheHeaders(w http.ResponseWriter, etag string, modified time.Time) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d",
+		int(addrsMaxAge.Seconds())))
+	w.Header().Set("Vary", "Accept")
+}
+
+func writeNotModified(w http.ResponseWriter, etag string, modified time.Time) {
+	setAddrsCacheHeaders(w, etag, modified)
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusNotModified)
+}
+
+func httpGetAddrs(w http.ResponseWriter, r *http.Request, cfg *httpConfig, amgr *Manager, log *slog.Logger) {
+	goodHash, modified, changed := amgr.GoodVersion()
+	etag := addrsETag(r, goodHash)
+	if notModified(r, etag, modified) {
+		// Long-poll for a change when requested rather than reporting
+		// that nothing changed right away.
+		wait := addrsWait(r)
+		if wait == 0 {
+			writeNotModified(w, etag, modified)
+			return
+		}
+		_ = http.NewResponseController(w).SetWriteDeadline(
+			time.Now().Add(wait + cfg.WriteTimeout))
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-changed:
+			goodHash, modified, _ = amgr.GoodVersion()
+			etag = addrsETag(r, goodHash)
+		case <-timer.C:
+			writeNotModified(w, etag, modified)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+	setAddrsCacheHeaders(w, etag, modified)
+
+	nodes := amgr.GoodAddresses(addrFilter(r, cfg.MaxAddrs))
+	if addrsFormat(r, true) == api.FormatProtobuf {
+		b, err := proto.Marshal(rpcNodes(nodes))
+		if err != nil {
+			log.Error("httpGetAddrs: Marshal failed", "err", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Server", appName)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+		return
+	}
+	writeNodes(w, r, nodes, nodeCSVHeader, nodeCSVRecord, cfg.StreamTimeout, log)
+}
+
+func httpGetAddrsV2(w http.ResponseWriter, r *http.Request, cfg *httpConfig, amgr *Manager, log *slog.Logger) {
+	nodes := amgr.GoodAddressesV2(addrFilter(r, cfg.MaxAddrs))
+	writeNodes(w, r, nodes, nodeV2CSVHeader, nodeV2CSVRecord, cfg.StreamTimeout, log)
+}
+
+// CSV columns of the node records. The host must be the first column.
+var (
+	nodeCSVHeader   = []string{"host", "services", "pver"}
+	nodeV2CSVHeader = []string{"host", "services", "pver", "lastseen",
+		"lastsuccess", "uptime", "useragent", "height", "latencyms"}
+)
+
+func nodeCSVRecord(node api.Node) []string {
+	return []string{
+		node.Host,
+		strconv.FormatUint(node.Services, 10),
+		strconv.FormatUint(uint64(node.ProtocolVersion), 10),
+	}
+}
+
+func nodeV2CSVRecord(node api.NodeV2) []string {
+	return []string{
+		node.Host,
+		strconv.FormatUint(node.Services, 10),
+		strconv.FormatUint(uint64(node.ProtocolVersion), 10),
+		node.LastSeen.Format(time.RFC3339),
+		node.LastSuccess.Format(time.RFC3339),
+		strconv.FormatFloat(node.Uptime, 'f', -1, 64),
+		node.UserAgent,
+		strconv.FormatInt(node.Height, 10),
+		strconv.FormatInt(node.LatencyMS, 10),
+	}
+}
+
+func httpGetAddrsSigned(w http.ResponseWriter, r *http.Request, cfg *netConfig, amgr *Manager, log *slog.Logger) {
+	manifest := api.Manifest{
+		Network:   cfg.name,
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+		Nodes:     amgr.GoodAddresses(addrFilter(r, cfg.HTTP.MaxAddrs)),
+	}
+	b, err := json.Marshal(&manifest)
+	if err != nil {
+		log.Error("httpGetAddrsSigned: Marshal failed", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	key := cfg.HTTP.signingKey
+	signed := api.SignedManifest{
+		Manifest:  b,
+		Signature: hex.EncodeToString(ed25519.Sign(key, b)),
+		PublicKey: hex.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(&signed)
+	if err != nil {
+		log.Warn("httpGetAddrsSigned: Encode failed", "err", err)
+	}
+}
+
+// writeNodes writes nodes in the response format requested by r. The CSV and
+// text formats use header and record to convert the nodes to rows, which must
+// list the fields of T in declaration order. Writing the response may take up
+// to streamTimeout rather than the usual write timeout of the server, since
+// node lists can be large.
+func writeNodes[T any](w http.ResponseWriter, r *http.Request, nodes []T,
+	header []string, record func(T) []string, streamTimeout time.Duration,
+	log *slog.Logger) {
+
+	// Writers which do not support deadlines, such as the response cache,
+	// are not bound by the server timeouts anyway.
+	_ = http.NewResponseController(w).SetWriteDeadline(
+		time.Now().Add(streamTimeout))
+
+	// Replace the Server response header. When used with nginx's "server_tokens
+	// off;" and "proxy_pass_header Server;" options.
+	w.Header().Set("Server", appName)
+
+	// Only the selected fields are encoded when the fields query parameter
+	// is passed.
+	projection := newFieldProjection[T](r)
+
+	switch addrsFormat(r, false) {
+	case api.FormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		var err error
+		if projection != nil {
+			err = projection.encodeArray(w, nodes)
+		} else {
+			err = json.NewEncoder(w).Encode(nodes)
+		}
+		if err != nil {
+			log.Warn("httpGetAddrs: Encode failed", "err", err)
+		}
+		return
+
+	case api.FormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		cw := csv.NewWriter(w)
+		if projection != nil {
+			header = projection.project(header)
+		}
+		_ = cw.Write(header)
+		for _, node := range nodes {
+			row := record(node)
+			if projection != nil {
+				row = projection.project(row)
+			}
+			_ = cw.Write(row)
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			log.Warn("httpGetAddrs: CSV write failed", "err", err)
+		}
+		return
+
+	case api.FormatText:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		bw := bufio.NewWriter(w)
+		for _, node := range nodes {
+			bw.WriteString(record(node)[0])
+			bw.WriteByte('\n')
+		}
+		if err := bw.Flush(); err != nil {
+			log.Warn("httpGetAddrs: Write failed", "err", err)
+		}
+		return
+
+	case api.FormatProtobuf:
+		http.Error(w, "protobuf is not supported by this endpoint",
+			http.StatusNotAcceptable)
+		return
+	}
 
 	flush, ok := w.(http.Flusher)
 	if !ok {
@@ -57,56 +411,353 @@ func httpGetAddrs(w http.ResponseWriter, r *http.Request, amgr *Manager, log *lo
 	}
 
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8") // not a json array
-	// Replace the Server response header. When used with nginx's "server_tokens
-	// off;" and "proxy_pass_header Server;" options.
-	w.Header().Set("Server", appName)
 	w.WriteHeader(http.StatusOK)
 	flush.Flush()
 
 	enc := json.NewEncoder(w)
 
 	ctx := r.Context()
-	for _, node := range nodes {
+	for i := range nodes {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			err := enc.Encode(node)
+			var err error
+			if projection != nil {
+				err = projection.encode(w, &nodes[i])
+			} else {
+				err = enc.Encode(nodes[i])
+			}
 			if err != nil {
-				log.Printf("httpGetAddrs: Encode failed: %v", err)
+				log.Warn("httpGetAddrs: Encode failed", "err", err)
 			}
 			flush.Flush()
 		}
 	}
 }
 
-type server struct {
-	srv      *http.Server
-	listener net.Listener
-	log      *log.Logger
+func httpNodeInfo(w http.ResponseWriter, r *http.Request, defaultPort string,
+	amgr *Manager, log *slog.Logger) {
+
+	host := strings.TrimPrefix(r.URL.Path, api.NodeInfoPath)
+	addrPort, err := netip.ParseAddrPort(normalizeAddress(host, defaultPort))
+	if err != nil {
+		http.Error(w, "invalid host", http.StatusBadRequest)
+		return
+	}
+	addrPort = netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port())
+
+	info, ok := amgr.NodeInfo(addrPort)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(w).Encode(info)
+	if err != nil {
+		log.Warn("httpNodeInfo: Encode failed", "err", err)
+	}
 }
 
-func newServer(addr string, amgr *Manager, log *log.Logger) (*server, error) {
-	listener, err := net.Listen("tcp", addr)
+func httpStats(w http.ResponseWriter, amgr *Manager, cache *responseCache, log *slog.Logger) {
+	stats := amgr.Stats()
+	if cache != nil {
+		stats.Cache = cache.stats()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusOK)
+	err := json.NewEncoder(w).Encode(stats)
+	if err != nil {
+		log.Warn("httpStats: Encode failed", "err", err)
+	}
+}
+
+func httpSpec(w http.ResponseWriter, log *slog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(api.OpenAPISpec)
 	if err != nil {
-		return nil, err
+		log.Warn("httpSpec: Write failed", "err", err)
+	}
+}
+
+// maxSubmissionSize is the maximum size of a node submission request body.
+const maxSubmissionSize = 1024
+
+// submitLimiter limits the number of node submissions accepted per client IP
+// within a fixed window.
+type submitLimiter struct {
+	mtx    sync.Mutex
+	limit  int
+	window time.Duration
+	start  time.Time
+	counts map[netip.Addr]int
+}
+
+func newSubmitLimiter(limit int, window time.Duration) *submitLimiter {
+	return &submitLimiter{
+		limit:  limit,
+		window: window,
+		counts: make(map[netip.Addr]int),
+	}
+}
+
+// allow returns whether another submission from addr is accepted.
+func (l *submitLimiter) allow(addr netip.Addr) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[netip.Addr]int)
+	}
+	if l.counts[addr] >= l.limit {
+		return false
+	}
+	l.counts[addr]++
+	return true
+}
+
+func httpSubmit(w http.ResponseWriter, r *http.Request, defaultPort string,
+	limiter *submitLimiter, amgr *Manager, log *slog.Logger) {
+
+	w.Header().Set("Server", appName)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	client, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil || !limiter.allow(client.Addr().Unmap()) {
+		http.Error(w, "too many submissions", http.StatusTooManyRequests)
+		return
+	}
+
+	var submission api.Submission
+	body := http.MaxBytesReader(w, r.Body, maxSubmissionSize)
+	if err := json.NewDecoder(body).Decode(&submission); err != nil {
+		http.Error(w, "invalid submission", http.StatusBadRequest)
+		return
+	}
+
+	host := normalizeAddress(submission.Host, defaultPort)
+	addrPort, err := netip.ParseAddrPort(host)
+	if err != nil || addrPort.Port() == 0 {
+		http.Error(w, "invalid host", http.StatusBadRequest)
+		return
+	}
+	addrPort = netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port())
+	if !amgr.Accepts(addrPort.Addr()) {
+		http.Error(w, "host is not routable", http.StatusBadRequest)
+		return
+	}
+
+	if amgr.AddAddresses([]netip.AddrPort{addrPort}) == 0 {
+		// Already known.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	log.Info("Node submitted", "node", addrPort, "client", client.Addr())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func httpHealth(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Server", appName)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, "ok\n")
+}
+
+func httpReady(w http.ResponseWriter, cfg *httpConfig, amgr *Manager) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Server", appName)
+
+	good, lastSuccess := amgr.Status()
+	switch {
+	case good < cfg.ReadyMinNodes:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "not ready: %d good nodes, need %d\n", good,
+			cfg.ReadyMinNodes)
+	case time.Since(lastSuccess) > cfg.ReadyMaxAge:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "not ready: no successful probe since %v\n",
+			lastSuccess.Format(time.RFC3339))
+	default:
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "ready: %d good nodes\n", good)
+	}
+}
+
+type server struct {
+	srv       *http.Server
+	listeners []net.Listener
+	log       *slog.Logger
+}
+
+// serverTLSConfig returns the TLS configuration used to serve HTTPS, or nil
+// when the server is configured to serve plain HTTP.
+func serverTLSConfig(cfg *httpConfig) (*tls.Config, error) {
+	switch {
+	case len(cfg.AutoCert) > 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.AutoCert...),
+			Cache:      autocert.DirCache(cfg.AutoCertDir),
+		}
+		return m.TLSConfig(), nil
+
+	case cfg.TLSCert != "":
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			return nil, err
+		}
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"h2", "http/1.1"},
+			MinVersion:   tls.VersionTLS12,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// newServeMux returns the handler routing all HTTP API requests of the
+// network described by cfg. The routes must be kept in sync with
+// api.OpenAPISpec.
+func newServeMux(cfg *netConfig, amgr *Manager, log *slog.Logger) *http.ServeMux {
+	getAddrs := func(w http.ResponseWriter, r *http.Request) {
+		httpGetAddrs(w, r, &cfg.HTTP, amgr, log)
+	}
+	getAddrsV2 := func(w http.ResponseWriter, r *http.Request) {
+		httpGetAddrsV2(w, r, &cfg.HTTP, amgr, log)
+	}
+	var cache *responseCache
+	if cfg.HTTP.CacheTTL > 0 {
+		cache = newResponseCache(cfg.HTTP.CacheTTL)
+		getAddrs = cache.handler(getAddrs)
+		getAddrsV2 = cache.handler(getAddrsV2)
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(api.GetAddrsPath, func(w http.ResponseWriter, r *http.Request) {
-		httpGetAddrs(w, r, amgr, log)
+	mux.HandleFunc(api.GetAddrsPath, getAddrs)
+	mux.HandleFunc(api.GetAddrsV2Path, getAddrsV2)
+	if cfg.HTTP.signingKey != nil {
+		mux.HandleFunc(api.GetAddrsSignedPath, func(w http.ResponseWriter, r *http.Request) {
+			httpGetAddrsSigned(w, r, cfg, amgr, log)
+		})
+	}
+	mux.HandleFunc(api.NodeInfoPath, func(w http.ResponseWriter, r *http.Request) {
+		httpNodeInfo(w, r, cfg.netParams.DefaultPort, amgr, log)
+	})
+	mux.HandleFunc(api.StatsPath, func(w http.ResponseWriter, _ *http.Request) {
+		httpStats(w, amgr, cache, log)
+	})
+	if cfg.HTTP.SubmitLimit > 0 {
+		limiter := newSubmitLimiter(cfg.HTTP.SubmitLimit, time.Hour)
+		mux.HandleFunc(api.SubmitPath, func(w http.ResponseWriter, r *http.Request) {
+			httpSubmit(w, r, cfg.netParams.DefaultPort, limiter, amgr, log)
+		})
+	}
+	auth := newAuthorizer(&cfg.HTTP)
+	if auth.enabled() {
+		registerAdminHandlers(mux, cfg, auth, amgr, log)
+	}
+	mux.HandleFunc(api.OpenAPIPath, func(w http.ResponseWriter, _ *http.Request) {
+		httpSpec(w, log)
+	})
+	mux.HandleFunc(api.HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		httpHealth(w)
 	})
+	mux.HandleFunc(api.ReadyPath, func(w http.ResponseWriter, _ *http.Request) {
+		httpReady(w, &cfg.HTTP, amgr)
+	})
+
+	return mux
+}
+
+// registerSharedHandlers adds handler, which serves the HTTP API of the network
+// named name, to the mux of the shared listener, where its paths are prefixed
+// by the network name. The handler is shared with the listeners of the
+// network, so that they also share its response cache and submission limits.
+func registerSharedHandlers(mux *http.ServeMux, name string, handler http.Handler) {
+	prefix := "/" + name
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, handler))
+}
+
+// newSharedServeMux returns the mux of the shared listener, which only serves
+// the health check until the networks are registered.
+func newSharedServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(api.HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		httpHealth(w)
+	})
+	return mux
+}
+
+// newServer returns the server of the network described by cfg, which serves
+// handler on the listen addresses of the network. HTTPS is served when
+// tlsConfig is not nil.
+func newServer(cfg *netConfig, tlsConfig *tls.Config, handler http.Handler,
+	log *slog.Logger) (*server, error) {
+
+	return newHTTPServer(cfg.Listen, tlsConfig, handler, &cfg.HTTP, log)
+}
+
+// newHTTPServer returns a server for handler listening on all addrs and tuned
+// by cfg. It serves HTTPS when tlsConfig is not nil.
+func newHTTPServer(addrs []string, tlsConfig *tls.Config, handler http.Handler,
+	cfg *httpConfig, log *slog.Logger) (*server, error) {
 
 	srv := &http.Server{
-		Handler:      mux,
-		ReadTimeout:  defaultHTTPTimeout, // slow requests should not hold connections opened
-		WriteTimeout: defaultHTTPTimeout, // request to response time
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout, // slow requests should not hold connections opened
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout, // request to response time
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+	if tlsConfig != nil && cfg.DisableHTTP2 {
+		// The TLS configuration of a network is shared with its gRPC
+		// server, which requires HTTP/2, so only a copy is changed.
+		tlsConfig = tlsConfig.Clone()
+		var protos []string
+		for _, proto := range tlsConfig.NextProtos {
+			if proto != "h2" {
+				protos = append(protos, proto)
+			}
+		}
+		tlsConfig.NextProtos = protos
+		srv.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler))
+	}
+
+	listeners := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		listener, err := listenTCP(addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		if tlsConfig != nil {
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		listeners = append(listeners, listener)
 	}
 
 	return &server{
-		srv:      srv,
-		listener: listener,
-		log:      log,
+		srv:       srv,
+		listeners: listeners,
+		log:       log,
 	}, nil
 }
 
@@ -122,19 +773,21 @@ func (h *server) run(ctx context.Context) {
 		_ = h.srv.Shutdown(ctx)
 	}()
 
-	// Start webserver.
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
+	// Start webserver on all listeners.
+	for _, listener := range h.listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
 
-		h.log.Printf("Listening on %s", h.listener.Addr())
-		err := h.srv.Serve(h.listener)
-		// ErrServerClosed is expected from a graceful server shutdown, it can
-		// be ignored. Anything else should be logged.
-		if err != nil && !errors.Is(err, http.ErrServerClosed) {
-			h.log.Printf("unexpected (http.Server).Serve error: %v", err)
-		}
-	}()
+			h.log.Info("Listening", "addr", listener.Addr())
+			err := h.srv.Serve(listener)
+			// ErrServerClosed is expected from a graceful server shutdown, it
+			// can be ignored. Anything else should be logged.
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				h.log.Error("unexpected (http.Server).Serve error", "err", err)
+			}
+		}(listener)
+	}
 
 	wg.Wait()
 }
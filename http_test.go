@@ -0,0 +1,373 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrseeder/api"
+)
+
+func Test_AddrsFormat(t *testing.T) {
+	formatTests := map[string]struct {
+		target         string
+		protobuf       bool
+		accept         string
+		expectedFormat string
+	}{
+		"default": {
+			"/api/addrs",
+			true,
+			"",
+			api.FormatNDJSON,
+		},
+		"accept any": {
+			"/api/addrs",
+			true,
+			"*/*",
+			api.FormatNDJSON,
+		},
+		"accept json": {
+			"/api/addrs",
+			true,
+			"application/json",
+			api.FormatJSON,
+		},
+		"accept json with params": {
+			"/api/addrs",
+			true,
+			"text/html, application/json; q=0.9",
+			api.FormatJSON,
+		},
+		"query json": {
+			"/api/addrs?format=json",
+			true,
+			"",
+			api.FormatJSON,
+		},
+		"query overrides accept": {
+			"/api/addrs?format=ndjson",
+			true,
+			"application/json",
+			api.FormatNDJSON,
+		},
+		"accept csv": {
+			"/api/addrs",
+			true,
+			"text/csv",
+			api.FormatCSV,
+		},
+		"accept text": {
+			"/api/addrs",
+			true,
+			"text/plain; charset=utf-8",
+			api.FormatNDJSON,
+		},
+		"accept protobuf": {
+			"/api/addrs",
+			true,
+			"application/x-protobuf",
+			api.FormatProtobuf,
+		},
+		"query text": {
+			"/api/addrs?format=text",
+			true,
+			"",
+			api.FormatText,
+		},
+		"accept protobuf unsupported": {
+			"/api/v2/addrs",
+			false,
+			"application/x-protobuf, application/json",
+			api.FormatJSON,
+		},
+		"accept only protobuf unsupported": {
+			"/api/v2/addrs",
+			false,
+			"application/x-protobuf",
+			api.FormatNDJSON,
+		},
+		"query protobuf unsupported": {
+			"/api/v2/addrs?format=protobuf",
+			false,
+			"application/json",
+			api.FormatProtobuf,
+		},
+		"unknown query format": {
+			"/api/addrs?format=xml",
+			true,
+			"application/json",
+			api.FormatNDJSON,
+		},
+	}
+
+	for testName, test := range formatTests {
+		r := httptest.NewRequest("GET", test.target, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		actualFormat := addrsFormat(r, test.protobuf)
+		if actualFormat != test.expectedFormat {
+			t.Fatalf("%s: expected format %q, got %q",
+				testName, test.expectedFormat, actualFormat)
+		}
+	}
+}
+
+func Test_AddrFilter(t *testing.T) {
+	const maxAddrs = 100
+	filterTests := map[string]struct {
+		target         string
+		expectedFilter AddrFilter
+	}{
+		"default": {
+			"/api/addrs",
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"filters": {
+			"/api/addrs?ipversion=6&pver=9&services=1",
+			AddrFilter{IPVersion: 6, ProtocolVersion: 9, Services: 1,
+				Limit: defaultMaxAddresses},
+		},
+		"invalid ipversion": {
+			"/api/addrs?ipversion=5",
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"limit": {
+			"/api/addrs?limit=50",
+			AddrFilter{Limit: 50},
+		},
+		"limit capped": {
+			"/api/addrs?limit=5000",
+			AddrFilter{Limit: maxAddrs},
+		},
+		"invalid limit": {
+			"/api/addrs?limit=-1",
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"maxage": {
+			"/api/addrs?maxage=30",
+			AddrFilter{Limit: defaultMaxAddresses, MaxAge: 30 * time.Minute},
+		},
+		"invalid maxage": {
+			"/api/addrs?maxage=0",
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"sort": {
+			"/api/addrs?sort=latency",
+			AddrFilter{Limit: defaultMaxAddresses, Sort: api.SortLatency},
+		},
+		"invalid sort": {
+			"/api/addrs?sort=height",
+			AddrFilter{Limit: defaultMaxAddresses},
+		},
+		"first page": {
+			"/api/addrs?offset=0",
+			AddrFilter{Limit: defaultMaxAddresses, Paged: true},
+		},
+		"page": {
+			"/api/addrs?limit=20&offset=40",
+			AddrFilter{Limit: 20, Paged: true, Offset: 40},
+		},
+	}
+
+	for testName, test := range filterTests {
+		r := httptest.NewRequest("GET", test.target, nil)
+		actualFilter := addrFilter(r, maxAddrs)
+		if *actualFilter != test.expectedFilter {
+			t.Fatalf("%s: expected filter %+v, got %+v",
+				testName, test.expectedFilter, *actualFilter)
+		}
+	}
+}
+
+func Test_ETagMatch(t *testing.T) {
+	const etag = `W/"abc"`
+	matchTests := map[string]struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		"same":       {`W/"abc"`, true},
+		"strong":     {`"abc"`, true},
+		"any":        {"*", true},
+		"list":       {`"def", W/"abc"`, true},
+		"different":  {`W/"def"`, false},
+		"unquoted":   {"abc", false},
+		"empty list": {",", false},
+	}
+
+	for testName, test := range matchTests {
+		actual := etagMatch(test.ifNoneMatch, etag)
+		if actual != test.expected {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+}
+
+func Test_NotModified(t *testing.T) {
+	const etag = `W/"abc"`
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 600, time.UTC)
+	notModifiedTests := map[string]struct {
+		ifNoneMatch     string
+		ifModifiedSince string
+		expected        bool
+	}{
+		"unconditional": {"", "", false},
+		"etag match":    {etag, "", true},
+		"etag mismatch": {`W/"def"`, "", false},
+		"etag precedes date": {
+			`W/"def"`,
+			"Fri, 02 Jan 2026 03:04:05 GMT",
+			false,
+		},
+		"same second": {"", "Fri, 02 Jan 2026 03:04:05 GMT", true},
+		"later":       {"", "Fri, 02 Jan 2026 04:00:00 GMT", true},
+		"earlier":     {"", "Fri, 02 Jan 2026 03:04:04 GMT", false},
+		"invalid":     {"", "yesterday", false},
+	}
+
+	for testName, test := range notModifiedTests {
+		r := httptest.NewRequest("GET", "/api/addrs", nil)
+		if test.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", test.ifNoneMatch)
+		}
+		if test.ifModifiedSince != "" {
+			r.Header.Set("If-Modified-Since", test.ifModifiedSince)
+		}
+		actual := notModified(r, etag, modified)
+		if actual != test.expected {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+}
+
+func Test_OpenAPISpec(t *testing.T) {
+	var spec struct {
+		Paths map[string]json.RawMessage `json:"paths"`
+	}
+	err := json.Unmarshal(api.OpenAPISpec, &spec)
+	if err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &netConfig{
+		HTTP: httpConfig{
+			SubmitLimit: 1,
+			APITokens:   []string{"0123456789abcdef"},
+			signingKey:  ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+		},
+		netParams: chaincfg.MainNetParams(),
+	}
+	mux := newServeMux(cfg, amgr, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// Every path in the spec must be routed.
+	for path := range spec.Paths {
+		target := strings.ReplaceAll(path, "{host}", "127.0.0.1")
+		r := httptest.NewRequest("GET", target, nil)
+		if _, pattern := mux.Handler(r); pattern == "" {
+			t.Fatalf("spec path %q is not routed", path)
+		}
+	}
+
+	// Every route must be in the spec.
+	paths := []string{
+		api.GetAddrsPath,
+		api.GetAddrsV2Path,
+		api.GetAddrsSignedPath,
+		api.NodeInfoPath + "{host}",
+		api.StatsPath,
+		api.SubmitPath,
+		api.OpenAPIPath,
+		api.AdminBanPath,
+		api.AdminUnbanPath,
+		api.AdminPinPath,
+		api.AdminUnpinPath,
+		api.AdminAllowPath,
+		api.AdminUnallowPath,
+		api.AdminDenyPath,
+		api.AdminUndenyPath,
+		api.AdminRangesPath,
+		api.AdminPrunePath,
+		api.AdminFlushPath,
+		api.HealthPath,
+		api.ReadyPath,
+	}
+	for _, path := range paths {
+		if _, ok := spec.Paths[path]; !ok {
+			t.Fatalf("path %q is missing from the spec", path)
+		}
+	}
+	if len(spec.Paths) != len(paths) {
+		t.Fatalf("expected %d paths in the spec, got %d", len(paths),
+			len(spec.Paths))
+	}
+}
+
+func Test_HTTPSubmit(t *testing.T) {
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &netConfig{
+		HTTP:      httpConfig{SubmitLimit: 4},
+		netParams: chaincfg.MainNetParams(),
+	}
+	mux := newServeMux(cfg, amgr, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// The requests share the submission limit of their client, so they
+	// are run in order.
+	submitTests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+	}{
+		{"get", "GET", "", http.StatusMethodNotAllowed},
+		{"invalid json", "POST", `{"host":`, http.StatusBadRequest},
+		{"invalid host", "POST", `{"host":"seed.example.org"}`, http.StatusBadRequest},
+		{"unroutable host", "POST", `{"host":"10.0.0.1"}`, http.StatusBadRequest},
+		{"new node", "POST", `{"host":"8.8.8.8"}`, http.StatusAccepted},
+		{"limit exceeded", "POST", `{"host":"8.8.4.4"}`, http.StatusTooManyRequests},
+	}
+
+	for _, test := range submitTests {
+		r := httptest.NewRequest(test.method, api.SubmitPath,
+			strings.NewReader(test.body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != test.expectedStatus {
+			t.Fatalf("%s: expected status %d, got %d", test.name,
+				test.expectedStatus, w.Code)
+		}
+	}
+
+	// The submitted node is added with the default port of the network.
+	if _, ok := amgr.nodes["8.8.8.8:9108"]; !ok {
+		t.Fatal("expected submitted node to be added")
+	}
+
+	// Submissions are disabled without a limit.
+	cfg.HTTP.SubmitLimit = 0
+	mux = newServeMux(cfg, amgr, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r := httptest.NewRequest("POST", api.SubmitPath, strings.NewReader(`{"host":"8.8.4.4"}`))
+	if _, pattern := mux.Handler(r); pattern != "" {
+		t.Fatalf("expected submissions to be disabled, got route %q", pattern)
+	}
+}
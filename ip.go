@@ -4,9 +4,32 @@
 
 package main
 
-import "net/netip"
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+)
 
 var (
+	// rfc1122Net specifies the IPv4 "this network" address block as defined
+	// by RFC1122 (0.0.0.0/8).
+	rfc1122Net = netip.MustParsePrefix("0.0.0.0/8")
+
+	// rfc1112Net specifies the IPv4 reserved address block, including the
+	// limited broadcast address, as defined by RFC1112 (240.0.0.0/4).
+	rfc1112Net = netip.MustParsePrefix("240.0.0.0/4")
+
+	// rfc2544Net specifies the IPv4 block reserved for benchmarking network
+	// interconnect devices as defined by RFC2544 (198.18.0.0/15).
+	rfc2544Net = netip.MustParsePrefix("198.18.0.0/15")
+
+	// rfc3849Net specifies the IPv6 documentation address block as defined
+	// by RFC3849 (2001:DB8::/32).
+	rfc3849Net = netip.MustParsePrefix("2001:DB8::/32")
+
 	// rfc3964Net specifies the IPv6 to IPv4 encapsulation address block as
 	// defined by RFC3964 (2002::/16).
 	rfc3964Net = netip.MustParsePrefix("2002::/16")
@@ -19,35 +42,202 @@ var (
 	// RFC4843 (2001:10::/28).
 	rfc4843Net = netip.MustParsePrefix("2001:10::/28")
 
-	// rfc4862Net specifies the IPv6 stateless address autoconfiguration
-	// address block as defined by RFC4862 (FE80::/64).
-	rfc4862Net = netip.MustParsePrefix("FE80::/64")
+	// rfc5737Nets specify the IPv4 documentation address blocks TEST-NET-1,
+	// TEST-NET-2 and TEST-NET-3 as defined by RFC5737 (192.0.2.0/24,
+	// 198.51.100.0/24 and 203.0.113.0/24).
+	rfc5737Nets = []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("198.51.100.0/24"),
+		netip.MustParsePrefix("203.0.113.0/24"),
+	}
 
 	// rfc6598Net specifies the Carrier-Grade NAT address block as defined by
 	// RFC6598 (100.64.0.0/10).
 	rfc6598Net = netip.MustParsePrefix("100.64.0.0/10")
+
+	// rfc7526Net specifies the deprecated 6to4 relay anycast address block
+	// as defined by RFC7526 (192.88.99.0/24).
+	rfc7526Net = netip.MustParsePrefix("192.88.99.0/24")
+
+	// specialNets are the special purpose address blocks which are never
+	// assigned to public nodes.
+	specialNets = append([]netip.Prefix{
+		rfc1112Net,
+		rfc1122Net,
+		rfc2544Net,
+		rfc3849Net,
+		rfc3964Net,
+		rfc4380Net,
+		rfc4843Net,
+		rfc6598Net,
+		rfc7526Net,
+	}, rfc5737Nets...)
 )
 
+// isRoutable returns whether addr is a publicly routable unicast address.
+// IPv4-mapped IPv6 addresses are treated as the IPv4 address they map.
 func isRoutable(addr netip.Addr) bool {
-	if addr.IsLoopback() {
+	addr = addr.Unmap()
+	switch {
+	case !addr.IsValid(),
+		addr.IsLoopback(),
+		addr.IsUnspecified(),
+		addr.IsPrivate(),
+		addr.IsLinkLocalUnicast(),
+		addr.IsMulticast():
 		return false
 	}
+	return !inPrefixes(addr, specialNets)
+}
 
-	if addr.IsUnspecified() {
-		return false
+var (
+	// errUnroutableRange is returned by routingPolicy.check for addresses
+	// within the additional unroutable ranges.
+	errUnroutableRange = errors.New("address is within an unroutable range")
+
+	// errRangeDenied is returned by routingPolicy.check for addresses which
+	// are outside of the allowed ranges or within a denied range.
+	errRangeDenied = errors.New("address is not within the allowed ranges " +
+		"or is within a denied range")
+
+	// errNotRoutable is returned by routingPolicy.check for addresses which
+	// are not publicly routable.
+	errNotRoutable = errors.New("address is not publicly routable")
+)
+
+// routingPolicy decides which node addresses are accepted on a network. The
+// policy of a network is built by loadConfig and shared by its address
+// manager, which applies it to the nodes it adds, probes and serves, and the
+// checks of the configuration, so that they never disagree.
+type routingPolicy struct {
+	// acceptLocal accepts nodes on local, private and special purpose
+	// addresses, as run by simnet clusters.
+	acceptLocal bool
+
+	// unroutable are additional ranges which are never accepted, even when
+	// acceptLocal is set.
+	unroutable []netip.Prefix
+
+	// ranges are the allowed and denied ranges of the configuration.
+	ranges addrRanges
+}
+
+// check returns why nodes at addr are not accepted, or nil when they are. The
+// extra ranges, such as those managed with the admin API, apply on top of
+// those of the policy.
+func (p *routingPolicy) check(addr netip.Addr, extra addrRanges) error {
+	addr = addr.Unmap()
+	switch {
+	case inPrefixes(addr, p.unroutable):
+		return errUnroutableRange
+	case !rangesAllow(addr, p.ranges, extra):
+		return errRangeDenied
+	case !p.acceptLocal && !isRoutable(addr):
+		return errNotRoutable
+	}
+	return nil
+}
+
+// addrRanges are the CIDR ranges of addresses nodes are allowed from or
+// denied.
+type addrRanges struct {
+	Allow []netip.Prefix `json:"allow"`
+	Deny  []netip.Prefix `json:"deny"`
+}
+
+// rangesAllow reports whether nodes at addr are allowed by all sets of ranges.
+// Addresses denied by any set are never allowed. When any set allows ranges,
+// the address must be within one of them.
+func rangesAllow(addr netip.Addr, sets ...addrRanges) bool {
+	anyAllowed := false
+	for _, set := range sets {
+		if inPrefixes(addr, set.Deny) {
+			return false
+		}
+		anyAllowed = anyAllowed || len(set.Allow) > 0
+	}
+	if !anyAllowed {
+		return true
 	}
+	for _, set := range sets {
+		if inPrefixes(addr, set.Allow) {
+			return true
+		}
+	}
+	return false
+}
 
-	if addr.IsPrivate() {
-		return false
+// inPrefixes returns whether addr is within any of prefixes.
+func inPrefixes(addr netip.Addr, prefixes []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
 	}
+	return false
+}
 
-	if rfc3964Net.Contains(addr) ||
-		rfc4380Net.Contains(addr) ||
-		rfc4843Net.Contains(addr) ||
-		rfc4862Net.Contains(addr) ||
-		rfc6598Net.Contains(addr) {
-		return false
+// parsePrefix parses a CIDR range such as 192.0.2.0/24. A single address is
+// parsed as the range holding only that address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
 	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if prefix.Addr().Is4In6() {
+		return netip.Prefix{}, fmt.Errorf("netip.ParsePrefix(%q): "+
+			"IPv4-mapped IPv6 ranges are not supported", s)
+	}
+	return prefix.Masked(), nil
+}
+
+// loadPrefixFile reads the CIDR ranges listed in the file at path, such as a
+// bogon list. Each line holds a single range, and empty lines and text
+// following a # are ignored.
+func loadPrefixFile(path string) ([]netip.Prefix, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
 
-	return true
+	var prefixes []netip.Prefix
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		prefix, err := parsePrefix(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return prefixes, nil
+}
+
+// probeSubnet returns the subnet addr belongs to for the purposes of throttling
+// probes. This is the /24 for IPv4 and the /48 for IPv6 addresses, which are
+// the typical allocations to a single hosting operator.
+func probeSubnet(addr netip.Addr) netip.Prefix {
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, _ := addr.Prefix(bits)
+	return prefix
 }
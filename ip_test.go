@@ -6,6 +6,8 @@ package main
 
 import (
 	"net/netip"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -113,11 +115,49 @@ func Test_IsRoutable(t *testing.T) {
 			"fe80:0000:0000:0000:ffff:ffff:ffff:ffff",
 			false,
 		},
-		"ip6 outside RFC4862": {
+		"ip6 link-local outside RFC4862": {
 			"fe80:0000:0000:0001:0000:0000:0000:0000",
+			false,
+		},
+		"ip6 end link-local": {
+			"febf:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+			false,
+		},
+		"ip6 outside link-local": {
+			"fec0:0000:0000:0000:0000:0000:0000:0000",
+			true,
+		},
+
+		// RFC3927
+		"ip4 link-local": {
+			"169.254.1.1",
+			false,
+		},
+		"ip4 outside link-local": {
+			"169.255.0.1",
 			true,
 		},
 
+		// RFC1122
+		"ip4 inside RFC1122": {
+			"0.1.2.3",
+			false,
+		},
+
+		// RFC1112
+		"ip4 start RFC1112": {
+			"240.0.0.0",
+			false,
+		},
+		"ip4 broadcast": {
+			"255.255.255.255",
+			false,
+		},
+		"ip4 multicast before RFC1112": {
+			"239.255.255.255",
+			false,
+		},
+
 		// RFC4193
 		"ip6 start RFC4193": {
 			"fc00:0000:0000:0000:0000:0000:0000:0000",
@@ -149,6 +189,86 @@ func Test_IsRoutable(t *testing.T) {
 			"100.128.0.0",
 			true,
 		},
+
+		// RFC5737
+		"ip4 inside RFC5737 (TEST-NET-1)": {
+			"192.0.2.1",
+			false,
+		},
+		"ip4 inside RFC5737 (TEST-NET-2)": {
+			"198.51.100.255",
+			false,
+		},
+		"ip4 inside RFC5737 (TEST-NET-3)": {
+			"203.0.113.0",
+			false,
+		},
+		"ip4 outside RFC5737": {
+			"192.0.3.0",
+			true,
+		},
+
+		// RFC2544
+		"ip4 start RFC2544": {
+			"198.18.0.0",
+			false,
+		},
+		"ip4 end RFC2544": {
+			"198.19.255.255",
+			false,
+		},
+		"ip4 outside end RFC2544": {
+			"198.20.0.0",
+			true,
+		},
+
+		// RFC3849
+		"ip6 start RFC3849": {
+			"2001:0db8:0000:0000:0000:0000:0000:0000",
+			false,
+		},
+		"ip6 end RFC3849": {
+			"2001:0db8:ffff:ffff:ffff:ffff:ffff:ffff",
+			false,
+		},
+		"ip6 outside RFC3849": {
+			"2001:0db9:0000:0000:0000:0000:0000:0000",
+			true,
+		},
+
+		// RFC7526
+		"ip4 inside RFC7526": {
+			"192.88.99.1",
+			false,
+		},
+		"ip4 outside RFC7526": {
+			"192.88.100.0",
+			true,
+		},
+
+		// Multicast
+		"ip4 multicast": {
+			"224.0.0.1",
+			false,
+		},
+		"ip6 multicast": {
+			"ff02::1",
+			false,
+		},
+
+		// IPv4-mapped IPv6
+		"ip4-mapped public": {
+			"::ffff:8.8.8.8",
+			true,
+		},
+		"ip4-mapped private": {
+			"::ffff:10.0.0.1",
+			false,
+		},
+		"ip4-mapped RFC6598": {
+			"::ffff:100.64.0.1",
+			false,
+		},
 	}
 
 	for testName, test := range ipTests {
@@ -164,3 +284,151 @@ func Test_IsRoutable(t *testing.T) {
 		}
 	}
 }
+
+func Test_ProbeSubnet(t *testing.T) {
+	subnetTests := map[string]struct {
+		ip             string
+		expectedSubnet string
+	}{
+		"ip4": {
+			"8.8.8.8",
+			"8.8.8.0/24",
+		},
+		"ip4 end of subnet": {
+			"203.0.113.255",
+			"203.0.113.0/24",
+		},
+		"ip6": {
+			"2001:4860:4860::8888",
+			"2001:4860:4860::/48",
+		},
+		"ip6 end of subnet": {
+			"2001:4860:4860:ffff:ffff:ffff:ffff:ffff",
+			"2001:4860:4860::/48",
+		},
+	}
+
+	for testName, test := range subnetTests {
+		addr, err := netip.ParseAddr(test.ip)
+		if err != nil {
+			t.Fatalf("%s: failed to parse %v: %v",
+				testName, test.ip, err)
+		}
+		actualSubnet := probeSubnet(addr).String()
+		if actualSubnet != test.expectedSubnet {
+			t.Fatalf("%s: expected subnet %s for IP %s, got %s",
+				testName, test.expectedSubnet, test.ip, actualSubnet)
+		}
+	}
+}
+
+func Test_ParsePrefix(t *testing.T) {
+	prefixTests := map[string]struct {
+		in             string
+		expectedPrefix string
+		expectedErr    bool
+	}{
+		"ip4 range":         {"198.51.100.0/24", "198.51.100.0/24", false},
+		"ip4 range masked":  {"198.51.100.7/24", "198.51.100.0/24", false},
+		"ip4 address":       {"198.51.100.7", "198.51.100.7/32", false},
+		"ip4-mapped":        {"::ffff:198.51.100.7", "198.51.100.7/32", false},
+		"ip6 range":         {"2001:db8::/32", "2001:db8::/32", false},
+		"ip6 address":       {"2001:db8::1", "2001:db8::1/128", false},
+		"ip4-mapped range":  {"::ffff:198.51.100.0/120", "", true},
+		"invalid address":   {"198.51.100", "", true},
+		"invalid prefixlen": {"198.51.100.0/33", "", true},
+	}
+
+	for testName, test := range prefixTests {
+		prefix, err := parsePrefix(test.in)
+		if test.expectedErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", testName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", testName, err)
+		}
+		if prefix.String() != test.expectedPrefix {
+			t.Fatalf("%s: expected prefix %s, got %s", testName,
+				test.expectedPrefix, prefix)
+		}
+	}
+}
+
+func Test_LoadPrefixFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bogons.txt")
+	const list = `# Bogons
+198.51.100.0/24
+2001:db8::/32 # documentation
+
+203.0.113.7
+`
+	if err := os.WriteFile(path, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prefixes, err := loadPrefixFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"198.51.100.0/24", "2001:db8::/32", "203.0.113.7/32"}
+	if len(prefixes) != len(expected) {
+		t.Fatalf("expected %d prefixes, got %d", len(expected), len(prefixes))
+	}
+	for i, prefix := range prefixes {
+		if prefix.String() != expected[i] {
+			t.Fatalf("expected prefix %s, got %s", expected[i], prefix)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("198.51.100.0/24\nbogus\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPrefixFile(path); err == nil {
+		t.Fatal("expected error for invalid range")
+	}
+}
+
+func Test_RoutingPolicy(t *testing.T) {
+	policy := &routingPolicy{
+		unroutable: []netip.Prefix{netip.MustParsePrefix("8.8.4.0/24")},
+		ranges: addrRanges{
+			Deny: []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24")},
+		},
+	}
+	local := &routingPolicy{
+		acceptLocal: true,
+		unroutable:  []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")},
+	}
+	admin := addrRanges{
+		Allow: []netip.Prefix{netip.MustParsePrefix("8.0.0.0/8")},
+	}
+
+	policyTests := map[string]struct {
+		policy      *routingPolicy
+		ip          string
+		extra       addrRanges
+		expectedErr error
+	}{
+		"public":             {policy, "8.8.1.1", addrRanges{}, nil},
+		"private":            {policy, "10.0.0.1", addrRanges{}, errNotRoutable},
+		"unroutable range":   {policy, "8.8.4.4", addrRanges{}, errUnroutableRange},
+		"ip4-mapped range":   {policy, "::ffff:8.8.4.4", addrRanges{}, errUnroutableRange},
+		"denied":             {policy, "8.8.8.8", addrRanges{}, errRangeDenied},
+		"extra allowed":      {policy, "8.8.1.1", admin, nil},
+		"not extra allowed":  {policy, "9.9.9.9", admin, errRangeDenied},
+		"extra cannot allow": {policy, "8.8.8.8", admin, errRangeDenied},
+		"accepted local":     {local, "10.0.0.1", addrRanges{}, nil},
+		"accepted special":   {local, "192.0.2.1", addrRanges{}, nil},
+		"local in range":     {local, "10.1.0.1", addrRanges{}, errUnroutableRange},
+	}
+
+	for testName, test := range policyTests {
+		err := test.policy.check(netip.MustParseAddr(test.ip), test.extra)
+		if err != test.expectedErr {
+			t.Fatalf("%s: expected error %v, got %v", testName,
+				test.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd socket
+// activation.
+const listenFdsStart = 3
+
+var (
+	activatedOnce sync.Once
+	activatedMtx  sync.Mutex
+	activated     []net.Listener
+)
+
+// loadActivatedListeners loads the listening sockets passed to the process by
+// systemd socket activation, if any. The environment variables used for the
+// protocol are unset so they are not inherited by child processes.
+func loadActivatedListeners() {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return
+	}
+	nfds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || nfds <= 0 {
+		return
+	}
+
+	for fd := listenFdsStart; fd < listenFdsStart+nfds; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			// Not a stream socket.
+			continue
+		}
+		activated = append(activated, l)
+	}
+}
+
+// listenTCP returns a TCP listener bound to addr. A matching socket passed by
+// systemd socket activation is used when available, which allows binding to
+// privileged ports without ever running the process as root.
+func listenTCP(addr string) (net.Listener, error) {
+	activatedOnce.Do(loadActivatedListeners)
+
+	want, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := takeActivated(want); l != nil {
+		return l, nil
+	}
+
+	return net.Listen("tcp", addr)
+}
+
+// takeActivated removes the socket passed by systemd socket activation which
+// is bound to want from the unused activated sockets and returns it. It
+// returns nil when no such socket was passed.
+func takeActivated(want *net.TCPAddr) net.Listener {
+	activatedMtx.Lock()
+	defer activatedMtx.Unlock()
+	for i, l := range activated {
+		have, ok := l.Addr().(*net.TCPAddr)
+		if !ok || have.Port != want.Port || !sameListenIP(have.IP, want.IP) {
+			continue
+		}
+		activated = append(activated[:i], activated[i+1:]...)
+		return l
+	}
+	return nil
+}
+
+// closeUnusedActivated closes the sockets passed by systemd socket activation
+// which did not match any listen address. It is called once all listeners
+// are bound so that misconfigured sockets are reported rather than silently
+// accepting connections which are never served.
+func closeUnusedActivated(log *slog.Logger) {
+	activatedOnce.Do(loadActivatedListeners)
+
+	activatedMtx.Lock()
+	defer activatedMtx.Unlock()
+	for _, l := range activated {
+		log.Warn("Closing activated socket not matching any listen address",
+			"addr", l.Addr())
+		l.Close()
+	}
+	activated = nil
+}
+
+// sameListenIP returns whether two listening IPs are the same, treating all
+// forms of the unspecified address as equal.
+func sameListenIP(a, b net.IP) bool {
+	unspecified := func(ip net.IP) bool {
+		return ip == nil || ip.IsUnspecified()
+	}
+	if unspecified(a) || unspecified(b) {
+		return unspecified(a) && unspecified(b)
+	}
+	return a.Equal(b)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_SameListenIP(t *testing.T) {
+	ipTests := map[string]struct {
+		a, b     net.IP
+		expected bool
+	}{
+		"equal":                 {net.ParseIP("127.0.0.1"), net.ParseIP("127.0.0.1"), true},
+		"different":             {net.ParseIP("127.0.0.1"), net.ParseIP("127.0.0.2"), false},
+		"ip4-mapped":            {net.ParseIP("127.0.0.1"), net.ParseIP("::ffff:127.0.0.1"), true},
+		"nil and unspecified":   {nil, net.ParseIP("0.0.0.0"), true},
+		"unspecified ip4 ip6":   {net.IPv4zero, net.IPv6unspecified, true},
+		"unspecified and ip":    {net.IPv4zero, net.ParseIP("127.0.0.1"), false},
+		"ip and nil":            {net.ParseIP("::1"), nil, false},
+		"ip6 equal":             {net.ParseIP("::1"), net.ParseIP("0:0::1"), true},
+		"ip6 and ip4 loopbacks": {net.ParseIP("::1"), net.ParseIP("127.0.0.1"), false},
+	}
+
+	for testName, test := range ipTests {
+		actual := sameListenIP(test.a, test.b)
+		if actual != test.expected {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+}
+
+func Test_ActivatedListeners(t *testing.T) {
+	listen := func(addr string) net.Listener {
+		t.Helper()
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { l.Close() })
+		return l
+	}
+	port := func(l net.Listener) string {
+		return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+	}
+
+	// Stand in for the sockets passed by systemd.
+	activatedOnce.Do(func() {})
+	loopback := listen("127.0.0.1:0")
+	unspecified := listen("0.0.0.0:0")
+	unused := listen("127.0.0.1:0")
+	activatedMtx.Lock()
+	activated = []net.Listener{loopback, unspecified, unused}
+	activatedMtx.Unlock()
+	t.Cleanup(func() {
+		activatedMtx.Lock()
+		activated = nil
+		activatedMtx.Unlock()
+	})
+
+	listenTests := []struct {
+		name     string
+		addr     string
+		expected net.Listener
+	}{
+		{"loopback", "127.0.0.1:" + port(loopback), loopback},
+		{"unspecified as ip6", "[::]:" + port(unspecified), unspecified},
+	}
+	for _, test := range listenTests {
+		l, err := listenTCP(test.addr)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if l != test.expected {
+			l.Close()
+			t.Fatalf("%s: expected activated listener %v, got %v",
+				test.name, test.expected.Addr(), l.Addr())
+		}
+	}
+
+	// A socket is only used for a single listen address, so listening on
+	// its address again binds a new socket, which fails since the address
+	// is in use.
+	if l, err := listenTCP("127.0.0.1:" + port(loopback)); err == nil {
+		l.Close()
+		t.Fatal("expected activated listener to be used once")
+	}
+
+	// The unmatched socket is logged and closed.
+	var buf bytes.Buffer
+	closeUnusedActivated(slog.New(slog.NewTextHandler(&buf, nil)))
+	if !strings.Contains(buf.String(), unused.Addr().String()) {
+		t.Fatalf("expected unused socket to be logged, got %q", buf.String())
+	}
+	if _, err := unused.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Fatalf("expected unused socket to be closed, got %v", err)
+	}
+	loopback.(*net.TCPListener).SetDeadline(time.Now())
+	if _, err := loopback.Accept(); errors.Is(err, net.ErrClosed) {
+		t.Fatal("expected used socket to remain open")
+	}
+	activatedMtx.Lock()
+	remaining := len(activated)
+	activatedMtx.Unlock()
+	if remaining != 0 {
+		t.Fatalf("expected no remaining activated sockets, got %d", remaining)
+	}
+}
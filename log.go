@@ -0,0 +1,176 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Supported logging backends.
+const (
+	logBackendStdout   = "stdout"
+	logBackendSyslog   = "syslog"
+	logBackendJournald = "journald"
+)
+
+// Subsystems whose log level can be set individually.
+const (
+	subsysMain    = "main"
+	subsysManager = "amgr"
+	subsysCrawler = "crawl"
+	subsysP2P     = "p2p"
+	subsysHTTP    = "http"
+	subsysGRPC    = "grpc"
+)
+
+var logSubsystems = []string{subsysMain, subsysManager, subsysCrawler,
+	subsysP2P, subsysHTTP, subsysGRPC}
+
+// logLevels are the log levels of all subsystems and the destination of their
+// log records.
+type logLevels struct {
+	level      slog.Level
+	subsystems map[string]slog.Level
+
+	// w receives each formatted log record with a single Write. Backends
+	// with priority levels are passed records without timestamps, since they
+	// add their own.
+	w          io.Writer
+	priorities bool
+}
+
+// parseLogLevels parses a comma separated list of log levels. Each entry is
+// either a level for all subsystems, such as "debug", or a level for a single
+// subsystem, such as "crawl=debug".
+func parseLogLevels(s string) (*logLevels, error) {
+	levels := logLevels{
+		level:      slog.LevelInfo,
+		subsystems: make(map[string]slog.Level),
+		w:          os.Stdout,
+	}
+	for _, entry := range strings.Split(s, ",") {
+		subsystem, levelStr, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			levelStr = subsystem
+		}
+		var level slog.Level
+		err := level.UnmarshalText([]byte(levelStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q", levelStr)
+		}
+		if !ok {
+			levels.level = level
+			continue
+		}
+		if !slices.Contains(logSubsystems, subsystem) {
+			return nil, fmt.Errorf("unknown log subsystem %q (supported: %s)",
+				subsystem, strings.Join(logSubsystems, ", "))
+		}
+		levels.subsystems[subsystem] = level
+	}
+	return &levels, nil
+}
+
+// setBackend directs the log records of all subsystems to the named backend.
+func (l *logLevels) setBackend(backend string) error {
+	switch backend {
+	case logBackendStdout:
+		l.w, l.priorities = os.Stdout, false
+	case logBackendSyslog:
+		w, err := newSyslogWriter()
+		if err != nil {
+			return fmt.Errorf("unable to connect to syslog: %w", err)
+		}
+		l.w, l.priorities = w, true
+	case logBackendJournald:
+		l.w, l.priorities = &priorityWriter{write: writeJournald}, true
+	default:
+		return fmt.Errorf("unknown log backend %q", backend)
+	}
+	return nil
+}
+
+// withFile returns a copy of the log levels writing log records to the file at
+// path instead of the log backend. Records are appended to the file, which is
+// created if necessary. The returned file must be closed once the records are
+// written.
+func (l *logLevels) withFile(path string) (*logLevels, *os.File, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	levels := *l
+	levels.w, levels.priorities = f, false
+	return &levels, f, nil
+}
+
+// logger returns a logger for subsystem which writes records at or above the
+// level of the subsystem to the log backend.
+func (l *logLevels) logger(subsystem string) *slog.Logger {
+	level, ok := l.subsystems[subsystem]
+	if !ok {
+		level = l.level
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	if l.priorities {
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		}
+	}
+	h := slog.NewTextHandler(l.w, opts)
+	return slog.New(h).With("subsystem", subsystem)
+}
+
+// priorityWriter passes log records formatted by a slog.TextHandler without
+// timestamps to a backend with priority levels. The level attribute leading
+// each record is stripped and passed as the level of the remaining line.
+type priorityWriter struct {
+	write func(level slog.Level, line string) error
+}
+
+func (w *priorityWriter) Write(b []byte) (int, error) {
+	line := strings.TrimSuffix(string(b), "\n")
+	level := slog.LevelInfo
+	if rest, ok := strings.CutPrefix(line, slog.LevelKey+"="); ok {
+		levelStr, rest, _ := strings.Cut(rest, " ")
+		if level.UnmarshalText([]byte(levelStr)) == nil {
+			line = rest
+		}
+	}
+	if err := w.write(level, line); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// writeJournald writes line to stderr prefixed with the syslog priority of
+// level, which journald parses from the output of the services it runs.
+func writeJournald(level slog.Level, line string) error {
+	// Priorities from sd-daemon(3).
+	priority := 6 // SD_INFO
+	switch {
+	case level >= slog.LevelError:
+		priority = 3 // SD_ERR
+	case level >= slog.LevelWarn:
+		priority = 4 // SD_WARNING
+	case level < slog.LevelInfo:
+		priority = 7 // SD_DEBUG
+	}
+	_, err := fmt.Fprintf(os.Stderr, "<%d>%s\n", priority, line)
+	return err
+}
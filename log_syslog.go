@@ -0,0 +1,35 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+//
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"io"
+	"log/slog"
+	"log/syslog"
+)
+
+// newSyslogWriter returns a writer passing log records to the local syslog
+// daemon with the priority of their level.
+func newSyslogWriter() (io.Writer, error) {
+	sw, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, appName)
+	if err != nil {
+		return nil, err
+	}
+	write := func(level slog.Level, line string) error {
+		switch {
+		case level >= slog.LevelError:
+			return sw.Err(line)
+		case level >= slog.LevelWarn:
+			return sw.Warning(line)
+		case level >= slog.LevelInfo:
+			return sw.Info(line)
+		default:
+			return sw.Debug(line)
+		}
+	}
+	return &priorityWriter{write: write}, nil
+}
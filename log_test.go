@@ -0,0 +1,147 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_ParseLogLevels(t *testing.T) {
+	parseTests := map[string]struct {
+		in         string
+		level      slog.Level
+		subsystems map[string]slog.Level
+		wantErr    bool
+	}{
+		"global": {
+			in:    "debug",
+			level: slog.LevelDebug,
+		},
+		"subsystem only": {
+			in:         "crawl=debug",
+			level:      slog.LevelInfo,
+			subsystems: map[string]slog.Level{subsysCrawler: slog.LevelDebug},
+		},
+		"global and subsystems": {
+			in:    "warn, http=error,p2p=DEBUG",
+			level: slog.LevelWarn,
+			subsystems: map[string]slog.Level{
+				subsysHTTP: slog.LevelError,
+				subsysP2P:  slog.LevelDebug,
+			},
+		},
+		"invalid level": {
+			in:      "verbose",
+			wantErr: true,
+		},
+		"unknown subsystem": {
+			in:      "dns=debug",
+			wantErr: true,
+		},
+	}
+
+	for testName, test := range parseTests {
+		levels, err := parseLogLevels(test.in)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", testName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", testName, err)
+		}
+		if levels.level != test.level {
+			t.Fatalf("%s: expected level %v, got %v", testName, test.level,
+				levels.level)
+		}
+		if len(levels.subsystems) != len(test.subsystems) {
+			t.Fatalf("%s: expected %d subsystem levels, got %d", testName,
+				len(test.subsystems), len(levels.subsystems))
+		}
+		for subsystem, level := range test.subsystems {
+			if levels.subsystems[subsystem] != level {
+				t.Fatalf("%s: expected %s level %v, got %v", testName,
+					subsystem, level, levels.subsystems[subsystem])
+			}
+		}
+	}
+}
+
+func Test_PriorityWriter(t *testing.T) {
+	var gotLevel slog.Level
+	var gotLine string
+	w := &priorityWriter{write: func(level slog.Level, line string) error {
+		gotLevel, gotLine = level, line
+		return nil
+	}}
+	levels := &logLevels{level: slog.LevelDebug, w: w, priorities: true}
+	log := levels.logger(subsysCrawler)
+
+	writeTests := map[string]struct {
+		log   func(msg string, args ...any)
+		level slog.Level
+		line  string
+	}{
+		"debug": {
+			log:   log.Debug,
+			level: slog.LevelDebug,
+			line:  `msg=debug subsystem=crawl`,
+		},
+		"warn": {
+			log:   log.Warn,
+			level: slog.LevelWarn,
+			line:  `msg=warn subsystem=crawl`,
+		},
+		"error": {
+			log:   log.Error,
+			level: slog.LevelError,
+			line:  `msg=error subsystem=crawl`,
+		},
+	}
+
+	for testName, test := range writeTests {
+		test.log(testName)
+		if gotLevel != test.level {
+			t.Fatalf("%s: expected level %v, got %v", testName, test.level,
+				gotLevel)
+		}
+		if gotLine != test.line {
+			t.Fatalf("%s: expected line %q, got %q", testName, test.line,
+				gotLine)
+		}
+	}
+}
+
+func Test_LogLevelsWithFile(t *testing.T) {
+	levels, err := parseLogLevels("info")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "logs", "mainnet.log")
+	fileLevels, f, err := levels.withFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fileLevels.logger(subsysCrawler).With("net", "mainnet").Info("hello")
+	fileLevels.logger(subsysCrawler).Debug("filtered")
+	f.Close()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const want = "level=INFO msg=hello subsystem=crawl net=mainnet\n"
+	if !strings.HasSuffix(string(b), want) || strings.Count(string(b), "\n") != 1 {
+		t.Fatalf("expected log file to hold %q, got %q", want, b)
+	}
+	if levels.w != os.Stdout {
+		t.Fatal("withFile modified the original log levels")
+	}
+}
@@ -6,12 +6,17 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
 	"net/netip"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -29,24 +34,77 @@ type Node struct {
 	LastSeen        time.Time
 	ProtocolVersion uint32
 	IP              netip.AddrPort
+	UserAgent       string
+	Height          int64
+
+	// Attempts and Successes count the probes of the node and how many of
+	// them succeeded.
+	Attempts  uint64
+	Successes uint64
+
+	// Latency is the time the most recent successful probe took to complete
+	// the handshake with the node.
+	Latency time.Duration
+
+	// AdvertisedAddr is the address the node advertised for itself when it
+	// differs from IP. This typically indicates a misconfigured external
+	// address on the node.
+	AdvertisedAddr netip.AddrPort
+
+	// Pinned nodes are never pruned.
+	Pinned bool
+}
+
+// uptime returns the fraction of probes of the node which succeeded.
+func (n *Node) uptime() float64 {
+	if n.Attempts == 0 {
+		return 0
+	}
+	return float64(n.Successes) / float64(n.Attempts)
 }
 
 type Manager struct {
 	mtx sync.RWMutex
 
-	nodes     map[string]*Node
-	peersFile string
-	log       *log.Logger
+	// saveMtx serializes the saves of the persisted files from taking the
+	// snapshot until the file is replaced, so an older snapshot never
+	// replaces a newer one. It is acquired before mtx.
+	saveMtx sync.Mutex
+
+	nodes        map[string]*Node
+	peersFile    string
+	bans         map[netip.Addr]struct{}
+	bansFile     string
+	staleTimeout time.Duration
+	stableAge    time.Duration
+	crawlStats   api.CrawlStats
+	log          *slog.Logger
+
+	// policy decides which nodes are never added, probed or served. It is
+	// set by setPolicy. ranges are the allowed and denied ranges managed
+	// with the admin API on top of those of the policy, which are
+	// persisted in rangesFile.
+	policy     *routingPolicy
+	ranges     addrRanges
+	rangesFile string
+
+	// torExits are the addresses of Tor exit nodes, which are never good.
+	torExits map[netip.Addr]struct{}
+
+	// goodHash is a digest of the set of good nodes, including the
+	// services and protocol versions which are served with them, and
+	// goodModified the time it last changed. goodChanged is closed and
+	// replaced whenever the digest changes.
+	goodHash     [sha256.Size]byte
+	goodModified time.Time
+	goodChanged  chan struct{}
 }
 
 const (
-	// defaultMaxAddresses is the maximum number of addresses to return.
+	// defaultMaxAddresses is the number of addresses returned unless more
+	// are requested.
 	defaultMaxAddresses = 16
 
-	// defaultStaleTimeout is the time in which a host is considered
-	// stale.
-	defaultStaleTimeout = time.Hour
-
 	// dumpAddressInterval is the interval used to dump the address
 	// cache to disk for future use.
 	dumpAddressInterval = time.Minute * 5
@@ -54,6 +112,13 @@ const (
 	// peersFilename is the name of the file.
 	peersFilename = "nodes.json"
 
+	// bansFilename is the name of the file storing banned IPs.
+	bansFilename = "bans.json"
+
+	// rangesFilename is the name of the file storing the ranges allowed
+	// and denied with the admin API.
+	rangesFilename = "ranges.json"
+
 	// pruneAddressInterval is the interval used to run the address
 	// pruner.
 	pruneAddressInterval = time.Minute * 1
@@ -63,32 +128,88 @@ const (
 	pruneExpireTimeout = time.Hour * 24
 )
 
-func NewManager(dataDir string, log *log.Logger) (*Manager, error) {
+// NewManager returns an address manager which persists its nodes in dataDir.
+// Nodes which have not been successfully probed within staleTimeout are
+// considered stale, and nodes are only considered good once stableAge has
+// passed since their first successful probe.
+func NewManager(dataDir string, staleTimeout, stableAge time.Duration, log *slog.Logger) (*Manager, error) {
 	err := os.MkdirAll(dataDir, 0o700)
 	if err != nil {
 		return nil, err
 	}
 
 	amgr := Manager{
-		nodes:     make(map[string]*Node),
-		peersFile: filepath.Join(dataDir, peersFilename),
-		log:       log,
+		nodes:        make(map[string]*Node),
+		peersFile:    filepath.Join(dataDir, peersFilename),
+		bans:         make(map[netip.Addr]struct{}),
+		bansFile:     filepath.Join(dataDir, bansFilename),
+		rangesFile:   filepath.Join(dataDir, rangesFilename),
+		policy:       &routingPolicy{},
+		goodChanged:  make(chan struct{}),
+		staleTimeout: staleTimeout,
+		stableAge:    stableAge,
+		log:          log,
 	}
 
 	err = amgr.deserializePeers()
 	if err != nil {
-		log.Printf("Failed to parse file %s: %v", amgr.peersFile, err)
+		log.Warn("Failed to parse peers file", "file", amgr.peersFile,
+			"err", err)
 		// if it is invalid we nuke the old one unconditionally.
 		err = os.Remove(amgr.peersFile)
 		if err != nil {
-			log.Printf("Failed to remove corrupt peers file %s: %v",
-				amgr.peersFile, err)
+			log.Error("Failed to remove corrupt peers file",
+				"file", amgr.peersFile, "err", err)
 		}
 	}
 
+	err = amgr.deserializeBans()
+	if err != nil {
+		// Unlike the peers file, bans are never discarded silently.
+		return nil, err
+	}
+	err = amgr.deserializeRanges()
+	if err != nil {
+		return nil, err
+	}
+	amgr.updateGood()
+
 	return &amgr, nil
 }
 
+// setPolicy sets the policy deciding which nodes are routable. It must be
+// called before the manager is used. Until then, only nodes on publicly
+// routable addresses are accepted.
+func (m *Manager) setPolicy(policy *routingPolicy) {
+	m.mtx.Lock()
+	m.policy = policy
+	m.updateGood()
+	m.mtx.Unlock()
+}
+
+// routable reports whether nodes at addr may be added. The manager mutex must
+// be held for reads.
+func (m *Manager) routable(addr netip.Addr) bool {
+	return m.policy.check(addr, m.ranges) == nil
+}
+
+// setTorExits sets the addresses of Tor exit nodes. Nodes at these addresses
+// are still probed, but never marked good, since they frequently only relay
+// connections rather than being stable listening nodes.
+func (m *Manager) setTorExits(exits map[netip.Addr]struct{}) {
+	m.mtx.Lock()
+	m.torExits = exits
+	m.updateGood()
+	m.mtx.Unlock()
+}
+
+// Accepts reports whether nodes at addr may be added.
+func (m *Manager) Accepts(addr netip.Addr) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.routable(addr)
+}
+
 func (m *Manager) AddAddresses(addrPorts []netip.AddrPort) int {
 	var count int
 
@@ -99,7 +220,11 @@ func (m *Manager) AddAddresses(addrPorts []netip.AddrPort) int {
 		addrPort := netip.AddrPortFrom(addrPortT.Addr().Unmap(),
 			addrPortT.Port())
 
-		if !isRoutable(addrPort.Addr()) {
+		if !m.routable(addrPort.Addr()) {
+			continue
+		}
+
+		if _, banned := m.bans[addrPort.Addr()]; banned {
 			continue
 		}
 
@@ -124,10 +249,10 @@ func (m *Manager) AddAddresses(addrPorts []netip.AddrPort) int {
 	return count
 }
 
-// Addresses returns IPs that need to be tested again.
-func (m *Manager) Addresses() []netip.AddrPort {
-	addrs := make([]netip.AddrPort, 0, defaultMaxAddresses*8)
-	i := defaultMaxAddresses
+// Addresses returns up to n IPs that need to be tested again.
+func (m *Manager) Addresses(n int) []netip.AddrPort {
+	addrs := make([]netip.AddrPort, 0, n)
+	i := n
 
 	m.mtx.RLock()
 	now := time.Now()
@@ -135,8 +260,12 @@ func (m *Manager) Addresses() []netip.AddrPort {
 		if i == 0 {
 			break
 		}
-		if now.Sub(node.LastSuccess) < defaultStaleTimeout ||
-			now.Sub(node.LastAttempt) < defaultStaleTimeout {
+		// Nodes loaded from disk may have become unroutable.
+		if !m.routable(node.IP.Addr()) {
+			continue
+		}
+		if now.Sub(node.LastSuccess) < m.staleTimeout ||
+			now.Sub(node.LastAttempt) < m.staleTimeout {
 			continue
 		}
 		addrs = append(addrs, node.IP)
@@ -147,31 +276,229 @@ func (m *Manager) Addresses() []netip.AddrPort {
 	return addrs
 }
 
-func (m *Manager) GoodAddresses(ipversion, pver uint32, services wire.ServiceFlag) []api.Node {
-	addrs := make([]api.Node, 0, defaultMaxAddresses)
-	i := defaultMaxAddresses
+// isGood returns whether the node is known to be stable and online at the
+// passed time. The manager mutex must be held for reads.
+func (m *Manager) isGood(node *Node, now time.Time) bool {
+	// Nodes in ranges which became unroutable after they were added.
+	if !m.routable(node.IP.Addr()) {
+		return false
+	}
+
+	// Nodes at the addresses of Tor exits.
+	if _, ok := m.torExits[node.IP.Addr().Unmap()]; ok {
+		return false
+	}
+
+	// Nodes that aren't known to be be stable yet.
+	if node.FirstSuccess.IsZero() ||
+		now.Sub(node.FirstSuccess) < m.stableAge {
+		return false
+	}
+
+	// Nodes that do not seem to be online.
+	if node.LastSuccess.IsZero() ||
+		now.Sub(node.LastSuccess) >= m.staleTimeout {
+		return false
+	}
+
+	return true
+}
 
+// Status returns the number of good nodes and the time of the most recent
+// successful probe of any node.
+func (m *Manager) Status() (good int, lastSuccess time.Time) {
 	m.mtx.RLock()
 	now := time.Now()
 	for _, node := range m.nodes {
-		if i == 0 {
-			break
+		if m.isGood(node, now) {
+			good++
 		}
+		if node.LastSuccess.After(lastSuccess) {
+			lastSuccess = node.LastSuccess
+		}
+	}
+	m.mtx.RUnlock()
+
+	return good, lastSuccess
+}
+
+// AddrFilter describes the set of good nodes returned by GoodAddresses.
+type AddrFilter struct {
+	// IPVersion restricts the nodes to IPv4 (4) or IPv6 (6) addresses when
+	// set.
+	IPVersion uint32
 
-		// Skip nodes that aren't known to be be stable yet.
-		if node.FirstSuccess.IsZero() ||
-			now.Sub(node.FirstSuccess) < defaultStaleTimeout {
+	// ProtocolVersion is the minimum protocol version of the nodes.
+	ProtocolVersion uint32
+
+	// Services are the services all nodes must provide.
+	Services wire.ServiceFlag
+
+	// MaxAge restricts the nodes to those successfully probed within the
+	// duration when set.
+	MaxAge time.Duration
+
+	// Limit is the maximum number of nodes to return.
+	Limit int
+
+	// Sort selects the best nodes by one of the api.Sort* orders rather than
+	// a random selection when set.
+	Sort string
+
+	// Paged selects nodes in a stable order starting at Offset rather than a
+	// random selection, so that callers can page through all good nodes.
+	Paged  bool
+	Offset int
+}
+
+// nodeLess returns the ordering of nodes by the passed api.Sort* order. Nodes
+// which compare equal are ordered by address so the order is stable.
+func nodeLess(order string) func(a, b *Node) bool {
+	byAddr := func(a, b *Node) bool {
+		return a.IP.String() < b.IP.String()
+	}
+	switch order {
+	case api.SortUptime:
+		return func(a, b *Node) bool {
+			if ua, ub := a.uptime(), b.uptime(); ua != ub {
+				return ua > ub
+			}
+			return byAddr(a, b)
+		}
+	case api.SortLastSuccess:
+		return func(a, b *Node) bool {
+			if !a.LastSuccess.Equal(b.LastSuccess) {
+				return a.LastSuccess.After(b.LastSuccess)
+			}
+			return byAddr(a, b)
+		}
+	case api.SortLatency:
+		// Nodes with an unknown latency go last.
+		return func(a, b *Node) bool {
+			if a.Latency != b.Latency {
+				if a.Latency == 0 || b.Latency == 0 {
+					return b.Latency == 0
+				}
+				return a.Latency < b.Latency
+			}
+			return byAddr(a, b)
+		}
+	}
+	return byAddr
+}
+
+// CrawlCycle records a completed crawl cycle which started at start, took
+// duration and probed the passed number of nodes.
+func (m *Manager) CrawlCycle(start time.Time, duration time.Duration, probed int) {
+	m.mtx.Lock()
+	m.crawlStats.Cycles++
+	m.crawlStats.LastStart = start
+	m.crawlStats.LastDurationMS = duration.Milliseconds()
+	m.crawlStats.LastProbed = probed
+	m.updateGood()
+	m.mtx.Unlock()
+}
+
+// updateGood notifies waiters on GoodChanged when the set of good nodes, or the
+// services or protocol version of any of them, has changed since the last
+// call. The manager mutex must be held for writes.
+func (m *Manager) updateGood() {
+	now := time.Now()
+	good := make([]string, 0, len(m.nodes))
+	for k, node := range m.nodes {
+		if m.isGood(node, now) {
+			good = append(good, k)
+		}
+	}
+	sort.Strings(good)
+
+	h := sha256.New()
+	var b [12]byte
+	for _, k := range good {
+		node := m.nodes[k]
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		binary.LittleEndian.PutUint64(b[:8], uint64(node.Services))
+		binary.LittleEndian.PutUint32(b[8:], node.ProtocolVersion)
+		h.Write(b[:])
+	}
+	var goodHash [sha256.Size]byte
+	copy(goodHash[:], h.Sum(nil))
+	if goodHash == m.goodHash {
+		return
+	}
+
+	m.goodHash = goodHash
+	m.goodModified = now
+	close(m.goodChanged)
+	m.goodChanged = make(chan struct{})
+}
+
+// GoodChanged returns a channel which is closed once the set of good nodes
+// changes. Changes are detected after each crawl cycle and whenever nodes are
+// removed.
+func (m *Manager) GoodChanged() <-chan struct{} {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.goodChanged
+}
+
+// GoodVersion returns a digest of the current set of good nodes, the time the
+// set last changed, and a channel which is closed once it changes again.
+func (m *Manager) GoodVersion() ([sha256.Size]byte, time.Time, <-chan struct{}) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.goodHash, m.goodModified, m.goodChanged
+}
+
+// Stats returns aggregate statistics about the known nodes and the crawler.
+func (m *Manager) Stats() *api.Stats {
+	stats := api.Stats{
+		IPVersions:       make(map[uint32]int),
+		ProtocolVersions: make(map[uint32]int),
+		Services:         make(map[uint64]int),
+		UserAgents:       make(map[string]int),
+	}
+
+	m.mtx.RLock()
+	now := time.Now()
+	stats.Nodes = len(m.nodes)
+	stats.Crawl = m.crawlStats
+	for _, node := range m.nodes {
+		if !m.isGood(node, now) {
 			continue
 		}
+		stats.GoodNodes++
+		if node.IP.Addr().Is4() {
+			stats.IPVersions[4]++
+		} else {
+			stats.IPVersions[6]++
+		}
+		stats.ProtocolVersions[node.ProtocolVersion]++
+		stats.Services[uint64(node.Services)]++
+		stats.UserAgents[node.UserAgent]++
+	}
+	m.mtx.RUnlock()
 
-		// Skip nodes that do not seem to be online.
-		if node.LastSuccess.IsZero() ||
-			now.Sub(node.LastSuccess) >= defaultStaleTimeout {
+	return &stats
+}
+
+// goodNodes returns copies of up to filter.Limit reliable nodes matching the
+// filter. Unless a page is requested, successive calls return differently
+// shuffled subsets of the matching nodes so load is distributed across all of
+// them.
+func (m *Manager) goodNodes(filter *AddrFilter) []Node {
+	var candidates []Node
+
+	m.mtx.RLock()
+	now := time.Now()
+	for _, node := range m.nodes {
+		if !m.isGood(node, now) {
 			continue
 		}
 
 		// Filter on ipversion
-		switch ipversion {
+		switch filter.IPVersion {
 		case 4:
 			if !node.IP.Addr().Is4() {
 				continue
@@ -183,38 +510,141 @@ func (m *Manager) GoodAddresses(ipversion, pver uint32, services wire.ServiceFla
 		}
 
 		// Filter on protocol version
-		if pver != 0 && node.ProtocolVersion < pver {
+		if filter.ProtocolVersion != 0 &&
+			node.ProtocolVersion < filter.ProtocolVersion {
 			continue
 		}
 
 		// Filter on services
-		if services != 0 && node.Services&services != services {
+		if filter.Services != 0 &&
+			node.Services&filter.Services != filter.Services {
+			continue
+		}
+
+		// Filter on last success
+		if filter.MaxAge != 0 && now.Sub(node.LastSuccess) > filter.MaxAge {
 			continue
 		}
 
+		candidates = append(candidates, *node)
+	}
+	m.mtx.RUnlock()
+
+	if filter.Paged || filter.Sort != "" {
+		less := nodeLess(filter.Sort)
+		sort.Slice(candidates, func(i, j int) bool {
+			return less(&candidates[i], &candidates[j])
+		})
+		if filter.Offset >= len(candidates) {
+			return nil
+		}
+		candidates = candidates[filter.Offset:]
+		if len(candidates) > filter.Limit {
+			candidates = candidates[:filter.Limit]
+		}
+		return candidates
+	}
+
+	// Select a random subset of the candidates by partially shuffling them.
+	n := len(candidates)
+	if n > filter.Limit {
+		n = filter.Limit
+	}
+	for i := 0; i < n; i++ {
+		j := i + rand.Intn(len(candidates)-i)
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+
+	return candidates[:n]
+}
+
+// GoodAddresses returns up to filter.Limit reliable nodes matching the filter.
+// Unless a page is requested, successive calls return differently shuffled
+// subsets of the matching nodes so load is distributed across all of them.
+func (m *Manager) GoodAddresses(filter *AddrFilter) []api.Node {
+	nodes := m.goodNodes(filter)
+	addrs := make([]api.Node, 0, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
 		addr := api.Node{
 			Host:            node.IP.String(),
 			Services:        uint64(node.Services),
 			ProtocolVersion: node.ProtocolVersion,
 		}
 		addrs = append(addrs, addr)
-		i--
 	}
-	m.mtx.RUnlock()
+	return addrs
+}
 
+// GoodAddressesV2 is the same as GoodAddresses, but returns the richer node
+// records of the v2 API.
+func (m *Manager) GoodAddressesV2(filter *AddrFilter) []api.NodeV2 {
+	nodes := m.goodNodes(filter)
+	addrs := make([]api.NodeV2, 0, len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		addr := api.NodeV2{
+			Host:            node.IP.String(),
+			Services:        uint64(node.Services),
+			ProtocolVersion: node.ProtocolVersion,
+			LastSeen:        node.LastSeen,
+			LastSuccess:     node.LastSuccess,
+			Uptime:          node.uptime(),
+			UserAgent:       node.UserAgent,
+			Height:          node.Height,
+			LatencyMS:       node.Latency.Milliseconds(),
+		}
+		addrs = append(addrs, addr)
+	}
 	return addrs
 }
 
+// NodeInfo returns the full record of the node at addrPort and whether it is
+// known.
+func (m *Manager) NodeInfo(addrPort netip.AddrPort) (api.NodeInfo, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	node, exists := m.nodes[addrPort.String()]
+	if !exists {
+		return api.NodeInfo{}, false
+	}
+
+	info := api.NodeInfo{
+		Host:            node.IP.String(),
+		Services:        uint64(node.Services),
+		ProtocolVersion: node.ProtocolVersion,
+		UserAgent:       node.UserAgent,
+		Height:          node.Height,
+		LastAttempt:     node.LastAttempt,
+		FirstSuccess:    node.FirstSuccess,
+		LastSuccess:     node.LastSuccess,
+		LastSeen:        node.LastSeen,
+		Attempts:        node.Attempts,
+		Successes:       node.Successes,
+		Reliability:     node.uptime(),
+		LatencyMS:       node.Latency.Milliseconds(),
+		Good:            m.isGood(node, time.Now()),
+	}
+	if node.AdvertisedAddr.IsValid() {
+		info.AdvertisedAddr = node.AdvertisedAddr.String()
+	}
+	return info, true
+}
+
 func (m *Manager) Attempt(addrPort netip.AddrPort) {
 	m.mtx.Lock()
 	node, exists := m.nodes[addrPort.String()]
 	if exists {
 		node.LastAttempt = time.Now()
+		node.Attempts++
 	}
 	m.mtx.Unlock()
 }
 
-func (m *Manager) Good(addrPort netip.AddrPort, services wire.ServiceFlag, pver uint32) {
+func (m *Manager) Good(addrPort netip.AddrPort, services wire.ServiceFlag, pver uint32,
+	userAgent string, height int64, latency time.Duration) {
+
 	m.mtx.Lock()
 	node, exists := m.nodes[addrPort.String()]
 	if exists {
@@ -222,6 +652,11 @@ func (m *Manager) Good(addrPort netip.AddrPort, services wire.ServiceFlag, pver
 
 		node.ProtocolVersion = pver
 		node.Services = services
+		node.UserAgent = userAgent
+		node.Height = height
+		node.Latency = latency
+		node.Successes++
+		node.AdvertisedAddr = netip.AddrPort{}
 		node.LastSuccess = now
 		if node.FirstSuccess.IsZero() {
 			node.FirstSuccess = now
@@ -230,6 +665,143 @@ func (m *Manager) Good(addrPort netip.AddrPort, services wire.ServiceFlag, pver
 	m.mtx.Unlock()
 }
 
+// AdvertisedMismatch records that the node at addrPort advertised itself as
+// the differing address advertised.
+func (m *Manager) AdvertisedMismatch(addrPort, advertised netip.AddrPort) {
+	m.mtx.Lock()
+	node, exists := m.nodes[addrPort.String()]
+	if exists {
+		node.AdvertisedAddr = advertised
+	}
+	m.mtx.Unlock()
+}
+
+// Ban removes all nodes with the passed IP and prevents them from being added
+// again. It returns the number of removed nodes.
+func (m *Manager) Ban(addr netip.Addr) (int, error) {
+	m.mtx.Lock()
+	m.bans[addr] = struct{}{}
+	var count int
+	for k, node := range m.nodes {
+		if node.IP.Addr() == addr {
+			delete(m.nodes, k)
+			count++
+		}
+	}
+	m.updateGood()
+	m.mtx.Unlock()
+
+	return count, m.saveBans()
+}
+
+// Unban allows nodes with the passed IP to be added again. It returns whether
+// the IP was banned.
+func (m *Manager) Unban(addr netip.Addr) (bool, error) {
+	m.mtx.Lock()
+	_, banned := m.bans[addr]
+	delete(m.bans, addr)
+	m.mtx.Unlock()
+
+	if !banned {
+		return false, nil
+	}
+	return true, m.saveBans()
+}
+
+// Pin marks the node at addrPort as never to be pruned, adding it first when
+// it is not yet known. It returns false when the address may not be added.
+func (m *Manager) Pin(addrPort netip.AddrPort) bool {
+	m.AddAddresses([]netip.AddrPort{addrPort})
+
+	m.mtx.Lock()
+	node, exists := m.nodes[addrPort.String()]
+	if exists {
+		node.Pinned = true
+	}
+	m.mtx.Unlock()
+
+	return exists
+}
+
+// Unpin allows the node at addrPort to be pruned again. It returns whether the
+// node is known.
+func (m *Manager) Unpin(addrPort netip.AddrPort) bool {
+	m.mtx.Lock()
+	node, exists := m.nodes[addrPort.String()]
+	if exists {
+		node.Pinned = false
+	}
+	m.mtx.Unlock()
+
+	return exists
+}
+
+// Allow adds prefix to the allowed ranges. It returns whether the range was
+// not allowed before.
+func (m *Manager) Allow(prefix netip.Prefix) (bool, error) {
+	return m.updateRanges(&m.ranges.Allow, prefix, true)
+}
+
+// Unallow removes prefix from the allowed ranges. It returns whether the range
+// was allowed with the admin API.
+func (m *Manager) Unallow(prefix netip.Prefix) (bool, error) {
+	return m.updateRanges(&m.ranges.Allow, prefix, false)
+}
+
+// Deny adds prefix to the denied ranges. Known nodes in the range are kept but
+// neither probed nor served until the range is no longer denied. It returns
+// whether the range was not denied before.
+func (m *Manager) Deny(prefix netip.Prefix) (bool, error) {
+	return m.updateRanges(&m.ranges.Deny, prefix, true)
+}
+
+// Undeny removes prefix from the denied ranges. It returns whether the range
+// was denied with the admin API.
+func (m *Manager) Undeny(prefix netip.Prefix) (bool, error) {
+	return m.updateRanges(&m.ranges.Deny, prefix, false)
+}
+
+// updateRanges adds prefix to or removes it from list, which is one of the
+// lists of ranges managed with the admin API, and saves the ranges when the
+// list changed. It returns whether the list changed.
+func (m *Manager) updateRanges(list *[]netip.Prefix, prefix netip.Prefix, add bool) (bool, error) {
+	m.mtx.Lock()
+	i := slices.Index(*list, prefix)
+	switch {
+	case add && i < 0:
+		*list = append(*list, prefix)
+	case !add && i >= 0:
+		*list = slices.Delete(*list, i, i+1)
+	default:
+		m.mtx.Unlock()
+		return false, nil
+	}
+	m.updateGood()
+	m.mtx.Unlock()
+
+	return true, m.saveRanges()
+}
+
+// Ranges returns the allowed and denied ranges.
+func (m *Manager) Ranges() *api.AddrRanges {
+	strs := func(prefixes []netip.Prefix) []string {
+		s := make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			s = append(s, prefix.String())
+		}
+		return s
+	}
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return &api.AddrRanges{
+		Allow:       strs(m.ranges.Allow),
+		Deny:        strs(m.ranges.Deny),
+		ConfigAllow: strs(m.policy.ranges.Allow),
+		ConfigDeny:  strs(m.policy.ranges.Deny),
+	}
+}
+
 // run is the main handler for the address manager.
 func (m *Manager) run(ctx context.Context) {
 	pruneAddressTicker := time.NewTicker(pruneAddressInterval)
@@ -257,8 +829,8 @@ func (m *Manager) prunePeers() {
 	protoMap := make(map[uint32]uint)
 	var count int
 	for k, node := range m.nodes {
-		// do not remove untried nodes
-		if node.LastAttempt.IsZero() {
+		// do not remove untried or pinned nodes
+		if node.LastAttempt.IsZero() || node.Pinned {
 			continue
 		}
 
@@ -278,13 +850,11 @@ func (m *Manager) prunePeers() {
 		protoMap[node.ProtocolVersion]++
 	}
 	l := len(m.nodes)
+	m.updateGood()
 	m.mtx.Unlock()
 
-	var t string
-	for proto, count := range protoMap {
-		t += fmt.Sprintf(" (%v:%v)", proto, count)
-	}
-	m.log.Printf("Pruned %d addresses: %d remaining%s", count, l, t)
+	m.log.Info("Pruned addresses", "pruned", count, "remaining", l,
+		"pvers", protoMap)
 }
 
 func (m *Manager) deserializePeers() error {
@@ -312,35 +882,135 @@ func (m *Manager) deserializePeers() error {
 	m.nodes = nodes
 	m.mtx.Unlock()
 
-	m.log.Printf("%d nodes loaded from %s", l, filePath)
+	m.log.Info("Nodes loaded", "nodes", l, "file", filePath)
 	return nil
 }
 
 func (m *Manager) savePeers() {
+	m.saveMtx.Lock()
+	defer m.saveMtx.Unlock()
 	m.mtx.RLock()
 	defer m.mtx.RUnlock()
 
-	// Write temporary peers file and then move it into place.
-	tmpfile := m.peersFile + ".new"
-	w, err := os.Create(tmpfile)
-	if err != nil {
-		m.log.Printf("Error opening file %s: %v", tmpfile, err)
+	if err := writeJSONFile(m.peersFile, &m.nodes); err != nil {
+		m.log.Error("Failed to save nodes", "err", err)
 		return
 	}
+
+	m.log.Info("Nodes saved", "nodes", len(m.nodes), "file", m.peersFile)
+}
+
+func (m *Manager) deserializeBans() error {
+	filePath := m.bansFile
+	_, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	r, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("%s error opening file: %v", filePath, err)
+	}
+	defer r.Close()
+
+	var bans []netip.Addr
+	dec := json.NewDecoder(r)
+	err = dec.Decode(&bans)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+
+	m.mtx.Lock()
+	for _, addr := range bans {
+		m.bans[addr] = struct{}{}
+	}
+	// Remove banned nodes which were saved before the ban.
+	for k, node := range m.nodes {
+		if _, banned := m.bans[node.IP.Addr()]; banned {
+			delete(m.nodes, k)
+		}
+	}
+	m.mtx.Unlock()
+
+	m.log.Info("Bans loaded", "bans", len(bans), "file", filePath)
+	return nil
+}
+
+func (m *Manager) saveBans() error {
+	m.saveMtx.Lock()
+	defer m.saveMtx.Unlock()
+	m.mtx.RLock()
+	bans := make([]netip.Addr, 0, len(m.bans))
+	for addr := range m.bans {
+		bans = append(bans, addr)
+	}
+	m.mtx.RUnlock()
+
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Less(bans[j])
+	})
+	return writeJSONFile(m.bansFile, bans)
+}
+
+func (m *Manager) deserializeRanges() error {
+	filePath := m.rangesFile
+	_, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	r, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("%s error opening file: %v", filePath, err)
+	}
+	defer r.Close()
+
+	var ranges addrRanges
+	dec := json.NewDecoder(r)
+	err = dec.Decode(&ranges)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+
+	m.mtx.Lock()
+	m.ranges = ranges
+	m.mtx.Unlock()
+
+	m.log.Info("Ranges loaded", "allow", len(ranges.Allow),
+		"deny", len(ranges.Deny), "file", filePath)
+	return nil
+}
+
+func (m *Manager) saveRanges() error {
+	m.saveMtx.Lock()
+	defer m.saveMtx.Unlock()
+	m.mtx.RLock()
+	ranges := addrRanges{
+		Allow: slices.Clone(m.ranges.Allow),
+		Deny:  slices.Clone(m.ranges.Deny),
+	}
+	m.mtx.RUnlock()
+
+	return writeJSONFile(m.rangesFile, &ranges)
+}
+
+// writeJSONFile writes the JSON encoding of v to a unique temporary file in the
+// directory of filePath and then moves it into place at filePath.
+func writeJSONFile(filePath string, v interface{}) error {
+	w, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.new")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file for %s: %v", filePath, err)
+	}
+	tmpfile := w.Name()
+	defer os.Remove(tmpfile) // Fails once renamed.
 	enc := json.NewEncoder(w)
-	if err := enc.Encode(&m.nodes); err != nil {
+	if err := enc.Encode(v); err != nil {
 		w.Close()
-		m.log.Printf("Failed to encode file %s: %v", tmpfile, err)
-		return
+		return fmt.Errorf("failed to encode file %s: %v", tmpfile, err)
 	}
 	if err := w.Close(); err != nil {
-		m.log.Printf("Error closing file %s: %v", tmpfile, err)
-		return
+		return fmt.Errorf("error closing file %s: %v", tmpfile, err)
 	}
-	if err := os.Rename(tmpfile, m.peersFile); err != nil {
-		m.log.Printf("Error writing file %s: %v", m.peersFile, err)
-		return
+	if err := os.Rename(tmpfile, filePath); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
 	}
-
-	m.log.Printf("%d nodes saved to %s", len(m.nodes), m.peersFile)
+	return nil
 }
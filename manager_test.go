@@ -0,0 +1,270 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/dcrseeder/api"
+)
+
+func Test_NodeLess(t *testing.T) {
+	now := time.Now()
+	nodes := []Node{{
+		IP:          netip.MustParseAddrPort("10.0.0.1:9108"),
+		Attempts:    4,
+		Successes:   2,
+		LastSuccess: now.Add(-time.Minute),
+	}, {
+		IP:          netip.MustParseAddrPort("10.0.0.2:9108"),
+		Attempts:    4,
+		Successes:   4,
+		LastSuccess: now.Add(-time.Hour),
+		Latency:     200 * time.Millisecond,
+	}, {
+		IP:          netip.MustParseAddrPort("10.0.0.3:9108"),
+		Attempts:    4,
+		Successes:   3,
+		LastSuccess: now,
+		Latency:     100 * time.Millisecond,
+	}, {
+		IP:          netip.MustParseAddrPort("10.0.0.0:9108"),
+		Attempts:    2,
+		Successes:   1,
+		LastSuccess: now.Add(-time.Minute),
+	}}
+
+	sortTests := map[string]struct {
+		order    string
+		expected []string
+	}{
+		"address": {
+			"",
+			[]string{"10.0.0.0:9108", "10.0.0.1:9108", "10.0.0.2:9108", "10.0.0.3:9108"},
+		},
+		"uptime": {
+			api.SortUptime,
+			[]string{"10.0.0.2:9108", "10.0.0.3:9108", "10.0.0.0:9108", "10.0.0.1:9108"},
+		},
+		"last success": {
+			api.SortLastSuccess,
+			[]string{"10.0.0.3:9108", "10.0.0.0:9108", "10.0.0.1:9108", "10.0.0.2:9108"},
+		},
+		"latency": {
+			api.SortLatency,
+			[]string{"10.0.0.3:9108", "10.0.0.2:9108", "10.0.0.0:9108", "10.0.0.1:9108"},
+		},
+	}
+
+	for testName, test := range sortTests {
+		less := nodeLess(test.order)
+		sort.Slice(nodes, func(i, j int) bool {
+			return less(&nodes[i], &nodes[j])
+		})
+		for i := range nodes {
+			if nodes[i].IP.String() != test.expected[i] {
+				t.Fatalf("%s: expected %v at position %d, got %v", testName,
+					test.expected[i], i, nodes[i].IP)
+			}
+		}
+	}
+}
+
+func Test_IsGood(t *testing.T) {
+	amgr, err := NewManager(t.TempDir(), time.Hour, 10*time.Minute,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+
+	goodTests := map[string]struct {
+		firstSuccess time.Time
+		lastSuccess  time.Time
+		expected     bool
+	}{
+		"never probed":      {time.Time{}, time.Time{}, false},
+		"not yet stable":    {now.Add(-5 * time.Minute), now, false},
+		"stable":            {now.Add(-15 * time.Minute), now, true},
+		"stable but stale":  {now.Add(-2 * time.Hour), now.Add(-time.Hour), false},
+		"within stale time": {now.Add(-2 * time.Hour), now.Add(-50 * time.Minute), true},
+	}
+
+	for testName, test := range goodTests {
+		node := &Node{
+			IP:           netip.MustParseAddrPort("8.8.8.8:9108"),
+			FirstSuccess: test.firstSuccess,
+			LastSuccess:  test.lastSuccess,
+		}
+		actual := amgr.isGood(node, now)
+		if actual != test.expected {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+}
+
+func Test_GoodVersion(t *testing.T) {
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrPort := netip.MustParseAddrPort("8.8.8.8:9108")
+	amgr.AddAddresses([]netip.AddrPort{addrPort})
+	node := amgr.nodes[addrPort.String()]
+	node.FirstSuccess = time.Now().Add(-2 * time.Hour)
+	node.LastSuccess = time.Now()
+	node.Services = wire.SFNodeNetwork
+	node.ProtocolVersion = 10
+
+	// The version changes along with the values served for a good node.
+	changes := map[string]func(){
+		"services":         func() { node.Services |= wire.SFNodeCF },
+		"protocol version": func() { node.ProtocolVersion++ },
+	}
+	for testName, change := range changes {
+		amgr.mtx.Lock()
+		amgr.updateGood()
+		amgr.mtx.Unlock()
+		hash, _, changed := amgr.GoodVersion()
+
+		amgr.mtx.Lock()
+		change()
+		amgr.updateGood()
+		amgr.mtx.Unlock()
+		newHash, _, _ := amgr.GoodVersion()
+		if newHash == hash {
+			t.Fatalf("%s: expected the digest to change", testName)
+		}
+		select {
+		case <-changed:
+		default:
+			t.Fatalf("%s: expected waiters to be notified", testName)
+		}
+	}
+}
+
+func Test_AddrRanges(t *testing.T) {
+	dataDir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	amgr, err := NewManager(dataDir, time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amgr.setPolicy(&routingPolicy{
+		ranges: addrRanges{
+			Deny: []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24")},
+		},
+	})
+	if _, err := amgr.Allow(netip.MustParsePrefix("8.8.0.0/16")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := amgr.Deny(netip.MustParsePrefix("8.8.4.4/32")); err != nil {
+		t.Fatal(err)
+	}
+
+	rangeTests := map[string]struct {
+		addr     string
+		expected bool
+	}{
+		"allowed":          {"8.8.1.1", true},
+		"not allowed":      {"1.1.1.1", false},
+		"config denied":    {"8.8.8.8", false},
+		"denied":           {"8.8.4.4", false},
+		"ip4-mapped":       {"::ffff:8.8.1.1", true},
+		"allowed denied":   {"::ffff:8.8.4.4", false},
+		"other ip version": {"2001:4860::1", false},
+	}
+
+	for testName, test := range rangeTests {
+		actual := amgr.Accepts(netip.MustParseAddr(test.addr))
+		if actual != test.expected {
+			t.Fatalf("%s: expected %v, got %v", testName, test.expected,
+				actual)
+		}
+	}
+
+	// The ranges managed with the admin API persist across restarts, unlike
+	// those of the configuration.
+	amgr, err = NewManager(dataDir, time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranges := amgr.Ranges()
+	if len(ranges.Allow) != 1 || ranges.Allow[0] != "8.8.0.0/16" ||
+		len(ranges.Deny) != 1 || ranges.Deny[0] != "8.8.4.4/32" ||
+		len(ranges.ConfigDeny) != 0 {
+		t.Fatalf("unexpected ranges after reload: %+v", ranges)
+	}
+
+	undenied, err := amgr.Undeny(netip.MustParsePrefix("8.8.4.4/32"))
+	if err != nil || !undenied {
+		t.Fatalf("expected range to be undenied, got %v, %v", undenied, err)
+	}
+	undenied, err = amgr.Undeny(netip.MustParsePrefix("8.8.4.4/32"))
+	if err != nil || undenied {
+		t.Fatalf("expected range to no longer be denied, got %v, %v",
+			undenied, err)
+	}
+	if !amgr.Accepts(netip.MustParseAddr("8.8.4.4")) {
+		t.Fatal("expected undenied address to be accepted")
+	}
+}
+
+func Test_ConcurrentSaves(t *testing.T) {
+	dataDir := t.TempDir()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	amgr, err := NewManager(dataDir, time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent admin requests and peer dumps must each leave a complete
+	// file behind.
+	const bans = 32
+	var wg sync.WaitGroup
+	for i := 0; i < bans; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			addr := netip.AddrFrom4([4]byte{8, 8, 8, byte(i)})
+			if _, err := amgr.Ban(addr); err != nil {
+				t.Error(err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			amgr.savePeers()
+		}()
+	}
+	wg.Wait()
+
+	amgr, err = NewManager(dataDir, time.Hour, time.Hour, log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(amgr.bans) != bans {
+		t.Fatalf("expected %d bans, got %d", bans, len(amgr.bans))
+	}
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".new") {
+			t.Fatalf("expected no temporary files, got %s", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sdNotify sends state, such as "READY=1", to the systemd service manager.
+// It does nothing when the process was not started by systemd with a
+// notification socket, e.g. by a unit with Type=notify.
+func sdNotify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	// Sockets in the abstract namespace are prefixed by '@', which is
+	// handled by the net package.
+	conn, err := net.DialUnix("unixgram", nil,
+		&net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval returns the interval at which systemd expects watchdog
+// pings from this process, or zero when the watchdog is disabled.
+func watchdogInterval() time.Duration {
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" {
+		if pid != strconv.Itoa(os.Getpid()) {
+			return 0
+		}
+	}
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// runWatchdog pings the systemd watchdog at half of interval until the context
+// is canceled. Each address manager must respond before a ping is sent, so a
+// deadlocked manager stops the pings and systemd restarts the process.
+func runWatchdog(ctx context.Context, interval time.Duration, amgrs []*Manager,
+	log *slog.Logger) {
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, amgr := range amgrs {
+			amgr.Status()
+		}
+		if err := sdNotify("WATCHDOG=1"); err != nil {
+			log.Warn("Failed to ping systemd watchdog", "err", err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_SdNotify(t *testing.T) {
+	// Nothing is sent without a notification socket.
+	t.Setenv("NOTIFY_SOCKET", "")
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatalf("unexpected error without socket: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "notify")
+	conn, err := net.ListenUnixgram("unixgram",
+		&net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets not supported: %v", err)
+	}
+	defer conn.Close()
+
+	t.Setenv("NOTIFY_SOCKET", socket)
+	if err := sdNotify("READY=1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf := make([]byte, 64)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if got := string(buf[:n]); got != "READY=1" {
+		t.Fatalf("expected state %q, got %q", "READY=1", got)
+	}
+}
+
+func Test_WatchdogInterval(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	intervalTests := map[string]struct {
+		usec     string
+		pid      string
+		interval time.Duration
+	}{
+		"disabled": {},
+		"enabled": {
+			usec:     "30000000",
+			interval: 30 * time.Second,
+		},
+		"this process": {
+			usec:     "1000",
+			pid:      pid,
+			interval: time.Millisecond,
+		},
+		"other process": {
+			usec: "1000",
+			pid:  "1",
+		},
+		"invalid": {
+			usec: "soon",
+		},
+	}
+
+	for testName, test := range intervalTests {
+		t.Setenv("WATCHDOG_USEC", test.usec)
+		t.Setenv("WATCHDOG_PID", test.pid)
+		if got := watchdogInterval(); got != test.interval {
+			t.Fatalf("%s: expected interval %v, got %v", testName,
+				test.interval, got)
+		}
+	}
+}
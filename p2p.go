@@ -0,0 +1,143 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/peer/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// maxInboundPeers is the maximum number of inbound peers which are handled
+// simultaneously. Further connections are closed immediately.
+const maxInboundPeers = 32
+
+// inboundListener accepts inbound connections from peers on the network and
+// records the addresses they gossip. This augments the active crawler with
+// passively discovered addresses, including those which are only reachable
+// from within NAT'd topologies.
+type inboundListener struct {
+	params   *chaincfg.Params
+	cfg      *crawlConfig
+	amgr     *Manager
+	listener net.Listener
+	log      *slog.Logger
+}
+
+func newInboundListener(addr string, params *chaincfg.Params, cfg *crawlConfig,
+	amgr *Manager, log *slog.Logger) (*inboundListener, error) {
+
+	listener, err := listenTCP(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inboundListener{
+		params:   params,
+		cfg:      cfg,
+		amgr:     amgr,
+		listener: listener,
+		log:      log,
+	}, nil
+}
+
+// handlePeer performs the handshake with an inbound peer, requests its known
+// addresses and records them before disconnecting.
+func (l *inboundListener) handlePeer(ctx context.Context, conn net.Conn) {
+	onaddr := make(chan struct{}, 1)
+	verack := make(chan struct{}, 1)
+	config := peer.Config{
+		UserAgentName:    l.cfg.userAgentName,
+		UserAgentVersion: l.cfg.userAgentVersion,
+		Net:              l.params.Net,
+		DisableRelayTx:   true,
+
+		Listeners: peer.MessageListeners{
+			OnAddr: func(p *peer.Peer, msg *wire.MsgAddr) {
+				added := l.amgr.AddAddresses(addrsFromMsg(msg))
+				l.log.Debug("Received addresses from inbound peer",
+					"peer", p.Addr(), "addrs", len(msg.AddrList), "new", added)
+				select {
+				case onaddr <- struct{}{}:
+				default:
+				}
+			},
+			OnVerAck: func(p *peer.Peer, _ *wire.MsgVerAck) {
+				select {
+				case verack <- struct{}{}:
+				default:
+				}
+			},
+		},
+	}
+
+	p := peer.NewInboundPeer(&config)
+	p.AssociateConnection(conn)
+	defer p.Disconnect()
+
+	select {
+	case <-verack:
+		p.QueueMessage(wire.NewMsgGetAddr(), nil)
+	case <-time.After(l.cfg.NodeTimeout):
+		return
+	case <-ctx.Done():
+		return
+	}
+
+	select {
+	case <-onaddr:
+	case <-time.After(l.cfg.NodeTimeout):
+	case <-ctx.Done():
+	}
+}
+
+func (l *inboundListener) run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	// Close the listener once the context is canceled in order to unblock
+	// Accept.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		_ = l.listener.Close()
+	}()
+
+	l.log.Info("Accepting inbound peers", "addr", l.listener.Addr())
+	sem := make(chan struct{}, maxInboundPeers)
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				l.log.Error("unexpected Accept error", "err", err)
+			}
+			break
+		}
+
+		select {
+		case sem <- struct{}{}:
+		default:
+			// Too many peers already being handled.
+			conn.Close()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.handlePeer(ctx, conn)
+			<-sem
+		}()
+	}
+
+	wg.Wait()
+}
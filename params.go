@@ -0,0 +1,60 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// customParams are the chain parameters of a custom network as read from a
+// JSON file. Unset fields keep the values of the network they are based on.
+type customParams struct {
+	Name        string `json:"name"`
+	Net         uint32 `json:"net"`
+	DefaultPort string `json:"defaultport"`
+}
+
+// loadNetParams returns a copy of base with the chain parameters in the JSON
+// file at path applied.
+func loadNetParams(path string, base *chaincfg.Params) (*chaincfg.Params, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Unknown fields are rejected so that misspelled or unsupported
+	// parameters are not silently ignored.
+	var custom customParams
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&custom)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	params := *base
+	if custom.Name != "" {
+		params.Name = custom.Name
+	}
+	if custom.Net != 0 {
+		params.Net = wire.CurrencyNet(custom.Net)
+	}
+	if custom.DefaultPort != "" {
+		port, err := strconv.ParseUint(custom.DefaultPort, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("%s: invalid default port %q", path,
+				custom.DefaultPort)
+		}
+		params.DefaultPort = custom.DefaultPort
+	}
+	return &params, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+func Test_LoadNetParams(t *testing.T) {
+	base := chaincfg.SimNetParams()
+	paramsTests := map[string]struct {
+		json     string
+		expected func(*chaincfg.Params) bool
+	}{
+		"custom": {
+			`{"name":"privnet","net":305419896,"defaultport":"19999"}`,
+			func(p *chaincfg.Params) bool {
+				return p.Name == "privnet" && p.Net == wire.CurrencyNet(0x12345678) &&
+					p.DefaultPort == "19999"
+			},
+		},
+		"unset fields": {
+			`{"name":"privnet"}`,
+			func(p *chaincfg.Params) bool {
+				return p.Name == "privnet" && p.Net == base.Net &&
+					p.DefaultPort == base.DefaultPort
+			},
+		},
+		"invalid port": {
+			`{"defaultport":"70000"}`,
+			nil,
+		},
+		"unknown field": {
+			`{"name":"privnet","dnsseeds":[]}`,
+			nil,
+		},
+		"invalid json": {
+			`{"name":`,
+			nil,
+		},
+	}
+
+	for testName, test := range paramsTests {
+		path := filepath.Join(t.TempDir(), "params.json")
+		err := os.WriteFile(path, []byte(test.json), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params, err := loadNetParams(path, base)
+		if test.expected == nil {
+			if err == nil {
+				t.Fatalf("%s: expected error", testName)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", testName, err)
+		}
+		if !test.expected(params) {
+			t.Fatalf("%s: unexpected params %+v", testName, params)
+		}
+	}
+
+	if base.Name != "simnet" {
+		t.Fatalf("base params were modified")
+	}
+}
@@ -6,7 +6,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 )
@@ -26,7 +26,7 @@ func shutdownListener() (context.Context, context.CancelFunc) {
 		// Listen for initial shutdown signal and cancel the context.
 		select {
 		case sig := <-interruptChannel:
-			log.Printf("Received signal (%s). Shutting down...", sig)
+			slog.Info("Received signal. Shutting down...", "signal", sig)
 			cancel()
 		case <-ctx.Done():
 		}
@@ -35,7 +35,7 @@ func shutdownListener() (context.Context, context.CancelFunc) {
 		// the shutdown is in progress and the process is not hung.
 		for {
 			sig := <-interruptChannel
-			log.Printf("Received signal (%s). Already shutting down...", sig)
+			slog.Info("Received signal. Already shutting down...", "signal", sig)
 		}
 	}()
 
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// torExitFetchTimeout is the maximum time to fetch the Tor exit list.
+const torExitFetchTimeout = time.Minute
+
+// parseTorExits parses a list of Tor exit node addresses with one IP address
+// per line. Empty lines and # comments are ignored.
+func parseTorExits(r io.Reader) (map[netip.Addr]struct{}, error) {
+	exits := make(map[netip.Addr]struct{})
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid address %q", line, text)
+		}
+		exits[addr.Unmap()] = struct{}{}
+	}
+	return exits, scanner.Err()
+}
+
+// fetchTorExits fetches and parses the Tor exit list at url.
+func fetchTorExits(ctx context.Context, client *http.Client, url string) (map[netip.Addr]struct{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", appName)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return parseTorExits(resp.Body)
+}
+
+// runTorExits fetches the Tor exit list at url and passes it to the address
+// managers, then refreshes it at the passed interval until ctx is done. The
+// previous list is kept when a refresh fails.
+func runTorExits(ctx context.Context, url string, interval time.Duration,
+	amgrs []*Manager, log *slog.Logger) {
+
+	client := &http.Client{Timeout: torExitFetchTimeout}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		exits, err := fetchTorExits(ctx, client, url)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			log.Warn("Failed to fetch Tor exit list", "url", url, "err", err)
+		default:
+			log.Debug("Fetched Tor exit list", "exits", len(exits))
+			for _, amgr := range amgrs {
+				amgr.setTorExits(exits)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ParseTorExits(t *testing.T) {
+	parseTests := map[string]struct {
+		list     string
+		expected []string
+		valid    bool
+	}{
+		"empty": {"", nil, true},
+		"addresses": {
+			"192.0.2.1\n2001:db8::1\n",
+			[]string{"192.0.2.1", "2001:db8::1"},
+			true,
+		},
+		"comments and blank lines": {
+			"# exits\n\n192.0.2.1 # relay\n",
+			[]string{"192.0.2.1"},
+			true,
+		},
+		"ip4-mapped": {"::ffff:192.0.2.1\n", []string{"192.0.2.1"}, true},
+		"invalid":    {"192.0.2.1\nExitNode 0011BD2485AD45D984EC4159C88FC066E5E3300E\n", nil, false},
+	}
+
+	for testName, test := range parseTests {
+		exits, err := parseTorExits(strings.NewReader(test.list))
+		if (err == nil) != test.valid {
+			t.Fatalf("%s: expected valid %v, got error %v", testName,
+				test.valid, err)
+		}
+		if !test.valid {
+			continue
+		}
+		if len(exits) != len(test.expected) {
+			t.Fatalf("%s: expected %d exits, got %d", testName,
+				len(test.expected), len(exits))
+		}
+		for _, s := range test.expected {
+			if _, ok := exits[netip.MustParseAddr(s)]; !ok {
+				t.Fatalf("%s: expected exit %s", testName, s)
+			}
+		}
+	}
+}
+
+func Test_TorExitsNotGood(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		io.WriteString(w, "8.8.4.4\n")
+	}))
+	defer srv.Close()
+
+	amgr, err := NewManager(t.TempDir(), time.Hour, time.Hour,
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, s := range []string{"8.8.8.8:9108", "8.8.4.4:9108"} {
+		addrPort := netip.MustParseAddrPort(s)
+		amgr.AddAddresses([]netip.AddrPort{addrPort})
+		node := amgr.nodes[addrPort.String()]
+		node.FirstSuccess = now.Add(-2 * time.Hour)
+		node.LastSuccess = now
+	}
+
+	exits, err := fetchTorExits(context.Background(), srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amgr.setTorExits(exits)
+	if good, _ := amgr.Status(); good != 1 {
+		t.Fatalf("expected 1 good node, got %d", good)
+	}
+}
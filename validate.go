@@ -0,0 +1,335 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// validation accumulates the results of the checks run by validateConfig.
+type validation struct {
+	w      io.Writer
+	failed int
+}
+
+// warn reports a problem with subject which does not fail the validation.
+func (v *validation) warn(subject string, err error) {
+	fmt.Fprintf(v.w, "WARN  %s: %v\n", subject, err)
+}
+
+// check reports the result of checking subject, which failed when err is not
+// nil.
+func (v *validation) check(subject string, err error) {
+	if err != nil {
+		v.failed++
+		fmt.Fprintf(v.w, "FAIL  %s: %v\n", subject, err)
+		return
+	}
+	fmt.Fprintf(v.w, "ok    %s\n", subject)
+}
+
+// validateConfig runs the checks on cfg which are otherwise only performed
+// when starting the services, such as binding the listeners and writing the
+// data directories, and writes a report to w. It returns whether all checks
+// passed. The options themselves were already validated by loadConfig. No
+// files or directories are created, and listeners already in use are only
+// reported as warnings.
+func validateConfig(cfg *config, w io.Writer) bool {
+	v := &validation{w: w}
+	for _, path := range cfg.configFiles {
+		v.check("config file "+path, nil)
+	}
+	v.check("configuration options", nil)
+
+	listeners := listenAddrs(cfg)
+	for _, l := range listeners {
+		// The seeder being validated may already be running.
+		err := checkListen(l.addr)
+		if errors.Is(err, syscall.EADDRINUSE) {
+			v.warn(l.option+" "+l.addr, err)
+			continue
+		}
+		v.check(l.option+" "+l.addr, err)
+	}
+	conflicts := listenConflicts(listeners)
+	for _, err := range conflicts {
+		v.check("listener conflicts", err)
+	}
+	if len(conflicts) == 0 {
+		v.check("listener conflicts", nil)
+	}
+	conflicts = dirConflicts(cfg)
+	for _, err := range conflicts {
+		v.check("directory conflicts", err)
+	}
+	if len(conflicts) == 0 {
+		v.check("directory conflicts", nil)
+	}
+
+	for _, netCfg := range []*netConfig{cfg.Mainnet, cfg.Testnet, cfg.Simnet} {
+		if !netCfg.Enabled {
+			continue
+		}
+		name := netCfg.name
+
+		v.check(fmt.Sprintf("%s: network parameters %s (port %s)", name,
+			netCfg.netParams.Name, netCfg.netParams.DefaultPort), nil)
+
+		v.check(fmt.Sprintf("%s: seeder %s", name, netCfg.seederIP),
+			checkSeeder(netCfg))
+
+		v.check(fmt.Sprintf("%s: data directory %s", name, netCfg.dataDir),
+			checkWritableDir(netCfg.dataDir))
+		if netCfg.LogFile != "" {
+			v.check(fmt.Sprintf("%s: logfile %s", name, netCfg.LogFile),
+				checkWritableFile(netCfg.LogFile))
+		}
+		if netCfg.HTTP.AutoCertDir != "" {
+			v.check(fmt.Sprintf("%s: http.autocertdir %s", name,
+				netCfg.HTTP.AutoCertDir),
+				checkWritableDir(netCfg.HTTP.AutoCertDir))
+		}
+
+		if netCfg.HTTP.TLSCert != "" {
+			_, err := tls.LoadX509KeyPair(netCfg.HTTP.TLSCert,
+				netCfg.HTTP.TLSKey)
+			v.check(fmt.Sprintf("%s: http.tlscert %s and http.tlskey %s",
+				name, netCfg.HTTP.TLSCert, netCfg.HTTP.TLSKey), err)
+		}
+		if netCfg.HTTP.SigningKey != "" {
+			pub := netCfg.HTTP.signingKey.Public()
+			v.check(fmt.Sprintf("%s: http.signingkey %s (public key %x)",
+				name, netCfg.HTTP.SigningKey, pub), nil)
+		}
+	}
+
+	if v.failed > 0 {
+		fmt.Fprintf(w, "%d check(s) failed\n", v.failed)
+		return false
+	}
+	fmt.Fprintln(w, "Configuration is valid")
+	return true
+}
+
+// checkStartup returns the problems with cfg which would otherwise only be
+// detected after some of the services were already started, such as
+// listeners colliding across networks.
+func checkStartup(cfg *config) error {
+	errs := listenConflicts(listenAddrs(cfg))
+	errs = append(errs, dirConflicts(cfg)...)
+	for _, netCfg := range []*netConfig{cfg.Mainnet, cfg.Testnet, cfg.Simnet} {
+		if !netCfg.Enabled {
+			continue
+		}
+		if err := checkSeeder(netCfg); err != nil {
+			errs = append(errs, fmt.Errorf("%s.seeder %s: %w", netCfg.name,
+				netCfg.seederIP, err))
+		}
+		if err := checkWritableDir(netCfg.dataDir); err != nil {
+			errs = append(errs, fmt.Errorf("%s: data directory is not "+
+				"writable: %w", netCfg.name, err))
+		}
+		if netCfg.LogFile == "" {
+			continue
+		}
+		if err := checkWritableFile(netCfg.LogFile); err != nil {
+			errs = append(errs, fmt.Errorf("%s.logfile: %w", netCfg.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkSeeder returns an error when the seeder of a network would be rejected
+// by its address manager.
+func checkSeeder(cfg *netConfig) error {
+	err := cfg.policy.check(cfg.seederIP.Addr(), addrRanges{})
+	if errors.Is(err, errNotRoutable) {
+		return fmt.Errorf("%w; use the address of a public node", err)
+	}
+	return err
+}
+
+// listenAddr is an address a listener is bound to and the option setting it.
+type listenAddr struct {
+	option string
+	addr   string
+}
+
+// listenAddrs returns the addresses of all listeners configured by cfg.
+func listenAddrs(cfg *config) []listenAddr {
+	var addrs []listenAddr
+	if cfg.HTTPListen != "" {
+		addrs = append(addrs, listenAddr{"httplisten", cfg.HTTPListen})
+	}
+	for _, netCfg := range []*netConfig{cfg.Mainnet, cfg.Testnet, cfg.Simnet} {
+		if !netCfg.Enabled {
+			continue
+		}
+		for _, addr := range netCfg.Listen {
+			addrs = append(addrs, listenAddr{netCfg.name + ".listen", addr})
+		}
+		if netCfg.GRPCListen != "" {
+			addrs = append(addrs, listenAddr{netCfg.name + ".grpclisten",
+				netCfg.GRPCListen})
+		}
+		if netCfg.P2PListen != "" {
+			addrs = append(addrs, listenAddr{netCfg.name + ".p2plisten",
+				netCfg.P2PListen})
+		}
+	}
+	return addrs
+}
+
+// listenConflicts returns an error for each pair of listeners which cannot be
+// bound at the same time.
+func listenConflicts(addrs []listenAddr) []error {
+	var errs []error
+	for i, a := range addrs {
+		for _, b := range addrs[i+1:] {
+			if listenCollide(a.addr, b.addr) {
+				errs = append(errs, fmt.Errorf("%s %s conflicts with %s %s",
+					a.option, a.addr, b.option, b.addr))
+			}
+		}
+	}
+	return errs
+}
+
+// dirConflicts returns an error for each pair of enabled networks which would
+// share a data directory or certificate cache, such as through equal datadir
+// options or a paramsfile naming another network. The address managers of
+// such networks would overwrite each other's files.
+func dirConflicts(cfg *config) []error {
+	type netDir struct {
+		option string
+		path   string
+	}
+	var dirs []netDir
+	for _, netCfg := range []*netConfig{cfg.Mainnet, cfg.Testnet, cfg.Simnet} {
+		if !netCfg.Enabled {
+			continue
+		}
+		option := netCfg.name + " data directory"
+		if netCfg.DataDir != "" {
+			option = netCfg.name + ".datadir"
+		}
+		dirs = append(dirs, netDir{option, netCfg.dataDir})
+		if netCfg.HTTP.AutoCertDir != "" {
+			dirs = append(dirs, netDir{netCfg.name + ".http.autocertdir",
+				netCfg.HTTP.AutoCertDir})
+		}
+	}
+
+	abs := func(path string) string {
+		if p, err := filepath.Abs(path); err == nil {
+			return p
+		}
+		return filepath.Clean(path)
+	}
+	var errs []error
+	for i, a := range dirs {
+		for _, b := range dirs[i+1:] {
+			if abs(a.path) == abs(b.path) {
+				errs = append(errs, fmt.Errorf("%s %s conflicts with %s %s",
+					a.option, a.path, b.option, b.path))
+			}
+		}
+	}
+	return errs
+}
+
+// listenCollide returns whether listeners bound to the addresses a and b would
+// collide. Listeners on the same port collide when either of them is bound to
+// all interfaces. Host names are only compared by name.
+func listenCollide(a, b string) bool {
+	hostA, portA, err := net.SplitHostPort(a)
+	if err != nil {
+		return false
+	}
+	hostB, portB, err := net.SplitHostPort(b)
+	if err != nil {
+		return false
+	}
+	// Port 0 binds a random port.
+	if portA != portB || portA == "0" {
+		return false
+	}
+
+	ipA, ipB := net.ParseIP(hostA), net.ParseIP(hostB)
+	unspecified := func(host string, ip net.IP) bool {
+		return host == "" || ip != nil && ip.IsUnspecified()
+	}
+	switch {
+	case unspecified(hostA, ipA) || unspecified(hostB, ipB):
+		return true
+	case ipA != nil && ipB != nil:
+		return ipA.Equal(ipB)
+	}
+	return hostA == hostB
+}
+
+// checkListen returns an error when a TCP listener cannot be bound to addr.
+// The listener is closed right away.
+func checkListen(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return l.Close()
+}
+
+// nearestExisting returns the path of the nearest existing file or directory
+// at or above path, along with its file info.
+func nearestExisting(path string) (string, os.FileInfo, error) {
+	path = filepath.Clean(path)
+	for {
+		fi, err := os.Stat(path)
+		if err == nil {
+			return path, fi, nil
+		}
+		parent := filepath.Dir(path)
+		if !errors.Is(err, fs.ErrNotExist) || parent == path {
+			return "", nil, err
+		}
+		path = parent
+	}
+}
+
+// checkWritableFile returns an error when the file at path could not be opened
+// for appending, or created when it does not exist. Nothing is created.
+func checkWritableFile(path string) error {
+	fi, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return checkWritableDir(filepath.Dir(path))
+	case err != nil:
+		return err
+	case fi.IsDir():
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return checkAccess(path, false)
+}
+
+// checkWritableDir returns an error when files could not be created in the
+// directory at path, or the directory itself when it does not exist. Nothing
+// is created.
+func checkWritableDir(path string) error {
+	existing, fi, err := nearestExisting(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", existing)
+	}
+	return checkAccess(existing, true)
+}
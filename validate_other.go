@@ -0,0 +1,16 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+//
+//go:build !unix
+
+package main
+
+import "os"
+
+// checkAccess returns an error when the file at path does not exist. Access
+// permissions are only checked on Unix systems.
+func checkAccess(path string, _ bool) error {
+	_, err := os.Stat(path)
+	return err
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+func Test_ValidateConfig(t *testing.T) {
+	// Occupy a port to make the listener check warn.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	defer l.Close()
+	inUse := l.Addr().String()
+
+	newConfig := func(listen string) *config {
+		return &config{
+			Mainnet: &netConfig{
+				Enabled:   true,
+				Listen:    []string{listen},
+				name:      "mainnet",
+				netParams: chaincfg.MainNetParams(),
+				seederIP:  netip.MustParseAddrPort("8.8.8.8:9108"),
+				dataDir:   filepath.Join(t.TempDir(), "mainnet"),
+				policy:    &routingPolicy{},
+			},
+			Testnet: &netConfig{},
+			Simnet:  &netConfig{},
+		}
+	}
+
+	validateTests := map[string]struct {
+		cfg   *config
+		valid bool
+		fail  string
+	}{
+		"valid": {
+			cfg:   newConfig("127.0.0.1:0"),
+			valid: true,
+		},
+		"listener in use": {
+			cfg:   newConfig(inUse),
+			valid: true,
+			fail:  "WARN  mainnet.listen " + inUse,
+		},
+		"shared data directory": {
+			cfg: func() *config {
+				cfg := newConfig("127.0.0.1:0")
+				cfg.Testnet = &netConfig{
+					Enabled:   true,
+					name:      "testnet",
+					netParams: chaincfg.TestNet3Params(),
+					seederIP:  netip.MustParseAddrPort("8.8.4.4:19108"),
+					dataDir:   cfg.Mainnet.dataDir + string(filepath.Separator),
+					policy:    &routingPolicy{},
+				}
+				return cfg
+			}(),
+			fail: "FAIL  directory conflicts: mainnet data directory",
+		},
+		"data directory is a file": {
+			cfg: func() *config {
+				cfg := newConfig("127.0.0.1:0")
+				path := filepath.Join(t.TempDir(), "file")
+				if err := os.WriteFile(path, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				cfg.Mainnet.dataDir = filepath.Join(path, "mainnet")
+				return cfg
+			}(),
+			fail: "FAIL  mainnet: data directory",
+		},
+	}
+
+	for testName, test := range validateTests {
+		var report strings.Builder
+		valid := validateConfig(test.cfg, &report)
+		if valid != test.valid {
+			t.Fatalf("%s: expected valid %v, got %v:\n%s", testName,
+				test.valid, valid, report.String())
+		}
+		if test.fail != "" && !strings.Contains(report.String(), test.fail) {
+			t.Fatalf("%s: expected report to contain %q, got:\n%s",
+				testName, test.fail, report.String())
+		}
+	}
+
+	// Validation is a dry run which creates neither the data directory
+	// nor the log file.
+	cfg := newConfig("127.0.0.1:0")
+	cfg.Mainnet.LogFile = filepath.Join(t.TempDir(), "logs", "mainnet.log")
+	var report strings.Builder
+	if !validateConfig(cfg, &report) {
+		t.Fatalf("expected valid config, got:\n%s", report.String())
+	}
+	for _, path := range []string{cfg.Mainnet.dataDir, cfg.Mainnet.LogFile} {
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected %s to not be created, got %v", path, err)
+		}
+	}
+}
+
+func Test_ListenCollide(t *testing.T) {
+	collideTests := map[string]struct {
+		a, b    string
+		collide bool
+	}{
+		"same address":          {"127.0.0.1:8000", "127.0.0.1:8000", true},
+		"different ports":       {"127.0.0.1:8000", "127.0.0.1:8001", false},
+		"different addresses":   {"127.0.0.1:8000", "127.0.0.2:8000", false},
+		"ipv4 wildcard":         {"0.0.0.0:8000", "127.0.0.1:8000", true},
+		"ipv6 wildcard":         {"127.0.0.1:8000", "[::]:8000", true},
+		"empty host":            {":8000", "[::1]:8000", true},
+		"ipv4 and ipv6":         {"127.0.0.1:8000", "[::1]:8000", false},
+		"same host name":        {"localhost:8000", "localhost:8000", true},
+		"host name and address": {"localhost:8000", "127.0.0.1:8000", false},
+		"random ports":          {"127.0.0.1:0", "127.0.0.1:0", false},
+	}
+
+	for testName, test := range collideTests {
+		if got := listenCollide(test.a, test.b); got != test.collide {
+			t.Fatalf("%s: expected collide %v, got %v", testName,
+				test.collide, got)
+		}
+	}
+}
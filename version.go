@@ -0,0 +1,12 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+// Version is the application version per the semantic versioning 2.0.0 spec
+// (https://semver.org/).
+//
+// It is defined as a variable so it can be overridden during the build
+// process with '-ldflags "-X main.Version=fullsemver"' if needed.
+var Version = "1.0.0-pre"